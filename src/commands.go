@@ -0,0 +1,408 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"openapi-generator/gen"
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/diff"
+	"openapi-generator/internal/ir"
+	"openapi-generator/internal/lint"
+)
+
+// command represents a subcommand of the program.
+type command struct {
+	// The command's arguments e.g., "[flags] <from> <to>".
+	args string
+	// The command's description.
+	summary string
+	// The command's implementation, given the command's flag set and the arguments following the
+	// command's name.
+	run func(fs *flag.FlagSet, args []string) error
+}
+
+// commandNames are the names of the commands, in order of appearance within the usage.
+var commandNames = []string{"generate", "validate", "lint", "diff", "bundle", "ir", "version"}
+
+// commands are the program's commands, by name.
+var commands = map[string]*command{
+	"generate": {
+		args:    "[flags]",
+		summary: "Generate code for the configured targets.",
+		run:     runGenerate,
+	},
+	"validate": {
+		args:    "[flags]",
+		summary: "Check that the specifications can be parsed.",
+		run:     runValidate,
+	},
+	"lint": {
+		args:    "[flags]",
+		summary: "Report the specifications' questionable constructs.",
+		run:     runLint,
+	},
+	"diff": {
+		args:    "<from> <to>",
+		summary: "Report the changes between two specifications, exiting with 1 on breaking ones.",
+		run:     runDiff,
+	},
+	"bundle": {
+		args:    "[flags]",
+		summary: "Write a specification as a single file, with its references to other files resolved.",
+		run:     runBundle,
+	},
+	"ir": {
+		args:    "dump [flags]",
+		summary: "Print the intermediate representation generated code is derived from, as JSON.",
+		run:     runIR,
+	},
+	"version": {
+		summary: "Print the program's version.",
+		run:     runVersion,
+	},
+}
+
+// printUsage prints the program's usage.
+func printUsage() {
+	lines := []string{"usage: openapi-parser <command> [flags]", "", "commands:"}
+	for _, name := range commandNames {
+		lines = append(lines, fmt.Sprintf("  %-10s %s", name, commands[name].summary))
+	}
+	lines = append(lines,
+		"",
+		"targets: "+strings.Join(gen.Targets(), ", "),
+		"",
+		"exit codes:",
+		fmt.Sprintf("  %d  success", exitOK),
+		fmt.Sprintf("  %d  failure e.g., breaking changes", exitFailure),
+		fmt.Sprintf("  %d  invalid command, flag or configuration", exitUsage),
+		fmt.Sprintf("  %d  invalid specification, or lint findings", exitSpec),
+		fmt.Sprintf("  %d  failure to write the output", exitWrite),
+	)
+	fmt.Fprintln(os.Stderr, strings.Join(lines, "\n"))
+}
+
+// newFlagSet returns a new flag set for the given command.
+func newFlagSet(name string, cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: openapi-parser %s %s\n\n%s\n\n", name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the given arguments; invalid ones are returned as `usageError`.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		// The flag set reports the problem, alongside the command's usage.
+		return &usageError{Err: err, reported: true}
+	}
+	return nil
+}
+
+// configFlags represents the flags locating the configuration and overriding it.
+type configFlags struct {
+	config string
+	input  string
+	output string
+	target string
+}
+
+// register registers the flags onto the given flag set; the output and target flags are optional.
+func (f *configFlags) register(fs *flag.FlagSet, withTarget bool) {
+	fs.StringVar(&f.config, "config", "", "Configuration file to use (default \""+config.DefaultFileName+"\", if present)")
+	fs.StringVar(&f.input, "input", "", "Specification file to use in place of the configured ones, or \"-\" for the standard input")
+	if withTarget {
+		fs.StringVar(&f.output, "output", "", "Directory to write the generated files to (single target only)")
+		fs.StringVar(&f.target, "target", "", fmt.Sprintf("Target to generate code for, one of %v", gen.Targets()))
+	}
+}
+
+// load loads the configuration, as per the flags; the default configuration file is only loaded
+// when present.
+func (f *configFlags) load() (*config.Config, error) {
+	path := f.config
+	if path == "" {
+		if _, err := os.Stat(config.DefaultFileName); err == nil {
+			path = config.DefaultFileName
+		}
+	}
+	if path == "" && f.input == "" {
+		return nil, &usageError{Err: fmt.Errorf("error: either a configuration file (%s) or --input must be given", config.DefaultFileName)}
+	}
+	cfg, err := config.Load(path, config.Flags{
+		Input:  f.input,
+		Output: f.output,
+		Target: f.target,
+	})
+	if err != nil {
+		return nil, &usageError{Err: fmt.Errorf("error: %w", err)}
+	}
+	return cfg, nil
+}
+
+// runGenerate generates code for the configured targets.
+func runGenerate(fs *flag.FlagSet, args []string) error {
+	var flags configFlags
+	flags.register(fs, true)
+	check := fs.Bool("check", false, "compare the generated files with those on disk, printing their differences, without writing them")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Targets) == 0 {
+		return &usageError{Err: fmt.Errorf("error: a target must be specified, one of %v", gen.Targets())}
+	}
+	for _, t := range cfg.Targets {
+		if t.Plugin == "" && !isTarget(t.Name) {
+			return &usageError{Err: fmt.Errorf("error: unknown target '%s', expected one of %v", t.Name, gen.Targets())}
+		}
+	}
+
+	if *check {
+		mismatches, err := gen.Check(cfg, VERSION)
+		if err != nil {
+			return err
+		}
+		for _, m := range mismatches {
+			fmt.Print(m.Diff)
+		}
+		if len(mismatches) > 0 {
+			return fmt.Errorf("error: %d generated file(s) out of date", len(mismatches))
+		}
+		fmt.Println("Up to date.")
+		return nil
+	}
+
+	// Generate; the specification files are read, alongside the files they reference, by the parser.
+	if err = gen.New(cfg, VERSION); err != nil {
+		return err
+	}
+	fmt.Println("Done.")
+	return nil
+}
+
+// isTarget checks whether the given target is supported.
+func isTarget(name string) bool {
+	for _, target := range gen.Targets() {
+		if target == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runValidate checks that the configured specifications can be parsed.
+func runValidate(fs *flag.FlagSet, args []string) error {
+	var flags configFlags
+	flags.register(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	for _, name := range cfg.InputNames() {
+		input := cfg.Inputs[name]
+		if _, err = gen.LoadDocument(input); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", input, err)
+			invalid++
+			continue
+		}
+		fmt.Printf("%s: valid\n", input)
+	}
+	if invalid > 0 {
+		return &specFailure{Err: fmt.Errorf("error: %d invalid specification(s)", invalid)}
+	}
+	return nil
+}
+
+// runLint reports the questionable constructs of the configured specifications.
+func runLint(fs *flag.FlagSet, args []string) error {
+	var flags configFlags
+	flags.register(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, name := range cfg.InputNames() {
+		input := cfg.Inputs[name]
+		doc, err := gen.LoadDocument(input)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		for _, finding := range lint.Lint(doc) {
+			fmt.Printf("%s: %s\n", input, finding)
+			count++
+		}
+	}
+	if count > 0 {
+		return &specFailure{Err: fmt.Errorf("error: %d finding(s)", count)}
+	}
+	return nil
+}
+
+// runDiff reports the changes between two specifications.
+func runDiff(fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return &usageError{Err: errors.New("error: exactly two specifications must be given")}
+	}
+	from, err := gen.LoadDocument(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	to, err := gen.LoadDocument(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(1), err)
+	}
+	changes := diff.Documents(from, to)
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if diff.HasBreaking(changes) {
+		return errors.New("error: breaking changes found")
+	}
+	return nil
+}
+
+// runBundle writes a specification as a single file.
+func runBundle(fs *flag.FlagSet, args []string) error {
+	var flags configFlags
+	flags.register(fs, false)
+	var outputFlag string
+	fs.StringVar(&outputFlag, "output", "", "File to write the bundle to (default standard output)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Inputs) != 1 {
+		return &usageError{Err: fmt.Errorf("error: a single input must be bundled, one of %v (see --input)", cfg.InputNames())}
+	}
+	var input string
+	for _, v := range cfg.Inputs {
+		input = v
+	}
+	b, err := gen.BundleDocument(input)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	if outputFlag == "" {
+		if _, err = os.Stdout.Write(b); err != nil {
+			return &gen.WriteError{Err: err}
+		}
+		return nil
+	}
+	if err = os.WriteFile(outputFlag, b, 0644); err != nil {
+		return &gen.WriteError{Err: err}
+	}
+	return nil
+}
+
+// runIR runs the subcommand of the intermediate representation i.e., "dump".
+func runIR(fs *flag.FlagSet, args []string) error {
+	if len(args) == 0 || args[0] != "dump" {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+		fs.Usage()
+		return &usageError{Err: errors.New("error: expected the 'dump' subcommand")}
+	}
+	var flags configFlags
+	flags.register(fs, false)
+	var outputFlag, targetFlag string
+	fs.StringVar(&outputFlag, "output", "", "File to write the representation to (default standard output)")
+	fs.StringVar(&targetFlag, "target", "", "Target whose transformation passes to apply, as configured (default none)")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	var target *config.Target
+	if targetFlag != "" {
+		target = &config.Target{Name: targetFlag}
+		for _, t := range cfg.Targets {
+			if t.Name == targetFlag {
+				target = t
+				break
+			}
+		}
+		if target.Plugin == "" && !isTarget(target.Name) {
+			return &usageError{Err: fmt.Errorf("error: unknown target '%s', expected one of %v", target.Name, gen.Targets())}
+		}
+	}
+	var input string
+	switch {
+	case target != nil && target.Input != "":
+		input = cfg.Inputs[target.Input]
+	case len(cfg.Inputs) == 1:
+		input = cfg.Inputs[cfg.InputNames()[0]]
+	default:
+		return &usageError{Err: fmt.Errorf("error: a single input must be dumped, one of %v (see --input)", cfg.InputNames())}
+	}
+	doc, err := gen.LoadDocument(input)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+	if target != nil {
+		if doc, err = gen.Transform(doc, target); err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+	}
+	b, err := json.MarshalIndent(ir.New(doc), "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if outputFlag == "" {
+		if _, err = os.Stdout.Write(b); err != nil {
+			return &gen.WriteError{Err: err}
+		}
+		return nil
+	}
+	if err = os.WriteFile(outputFlag, b, 0644); err != nil {
+		return &gen.WriteError{Err: err}
+	}
+	return nil
+}
+
+// runVersion prints the program's version.
+func runVersion(fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	fmt.Println("openapi-parser version " + VERSION)
+	fmt.Println("targets: " + strings.Join(gen.Targets(), ", "))
+	return nil
+}
@@ -0,0 +1,40 @@
+package gen
+
+// SpecError represents a failure to read or parse a specification.
+type SpecError struct {
+	Err error
+}
+
+func (e *SpecError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+// WriteError represents a failure to write generated files.
+type WriteError struct {
+	Err error
+}
+
+func (e *WriteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// PluginError represents a failure of a plugin to generate files.
+type PluginError struct {
+	Err error
+}
+
+func (e *PluginError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
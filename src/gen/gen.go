@@ -1,16 +1,53 @@
 package gen
 
 import (
-	"os"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 
-	"openapi-generator/gen/typescript"
+	"openapi-generator/internal/config"
 	"openapi-generator/internal/output"
 	"openapi-generator/internal/parser"
 	"openapi-generator/internal/slog"
+	"openapi-generator/internal/transform"
 )
 
-// New generates code for the given OpenAPI spec based on the given language extension.
-func New(b []byte, version string, extn Extension) error {
+// New generates code for each of the targets of the given configuration; specs may reference
+// other files, relative to their own directory.
+func New(cfg *config.Config, version string) error {
+	return generate(cfg, version, func(target *config.Target) (output.FS, error) {
+		return output.OpenFS(target.Output)
+	})
+}
+
+// Check generates code for each of the targets of the given configuration, in memory; the
+// generated files which differ from those on disk are returned.
+func Check(cfg *config.Config, version string) ([]*output.Mismatch, error) {
+	generated := make(map[*config.Target]*output.MemFS)
+	err := generate(cfg, version, func(target *config.Target) (output.FS, error) {
+		generated[target] = output.NewMemFS()
+		return generated[target], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]*output.Mismatch, 0)
+	for _, target := range cfg.Targets {
+		m, err := output.Compare(generated[target], target.Output)
+		if err != nil {
+			return nil, fmt.Errorf("target '%s': %w", target.Name, err)
+		}
+		mismatches = append(mismatches, m...)
+	}
+	return mismatches, nil
+}
+
+// generate generates code for each of the targets of the given configuration, writing it to the
+// file system opened by the given function.
+func generate(cfg *config.Config, version string, open func(target *config.Target) (output.FS, error)) error {
 	logger := slog.NewLogger("")
 	logger.Println("DEBUG=1: logs enabled")
 	logger.Println(`
@@ -22,42 +59,153 @@ Please double check the entirety of the logs before reaching a conclusion.
 -------------------------------------------------------------------------------------------------|
 `)
 
-	doc, err := parser.NewDocument(b)
+	// Each input is parsed once, as targets are generated from their own transformed copy of it.
+	docs := make(map[string]*parser.Document)
+	for _, target := range cfg.Targets {
+		doc, ok := docs[target.Input]
+		if !ok {
+			var err error
+			if doc, err = LoadDocument(cfg.Inputs[target.Input]); err != nil {
+				logger.Println("LoadDocument:", err)
+				return fmt.Errorf("target '%s': %w", target.Name, err)
+			}
+			docs[target.Input] = doc
+		}
+		hash, err := generationHash(doc, target, version)
+		if err != nil {
+			return fmt.Errorf("target '%s': %w", target.Name, err)
+		}
+		watermark := &output.Watermark{
+			Message:   cfg.Watermark,
+			Version:   version,
+			Hash:      hash,
+			Timestamp: cfg.Timestamp,
+		}
+		if err := generateTarget(target, doc, watermark, version, open, logger); err != nil {
+			return fmt.Errorf("target '%s': %w", target.Name, err)
+		}
+	}
+	return nil
+}
+
+// newGenerator returns the generator of the given target, be it a plugin or a registered one.
+func newGenerator(target *config.Target, version string, logger slog.Logger) (Generator, error) {
+	if target.Plugin != "" {
+		return newPluginGenerator(target, version, logger), nil
+	}
+	factory, ok := lookupGenerator(target.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown target, expected one of %v", Targets())
+	}
+	return factory(target, logger), nil
+}
+
+// Transform returns a copy of the given document, transformed by the passes enabled for the given
+// target; the document is left untouched.
+func Transform(doc *parser.Document, target *config.Target) (*parser.Document, error) {
+	g, err := newGenerator(target, "", slog.NewLogger(""))
 	if err != nil {
-		logger.Println("parser.NewDocument:", err)
-		return err
+		return nil, err
 	}
+	return transformDocument(doc, g, target)
+}
 
-	// Create directory if it doesn't exist.
-	outDir := getAppropriateDestination(extn)
-	if err = os.MkdirAll(outDir, 0755); err != nil {
-		logger.Println("os.MkdirAll:", err)
+// transformDocument returns a copy of the given document, transformed by the passes enabled for
+// the given target, as generated by the given generator.
+func transformDocument(doc *parser.Document, g Generator, target *config.Target) (*parser.Document, error) {
+	opts := &transform.Options{
+		Names: target.Overrides.Names,
+		Types: target.Overrides.Types,
+	}
+	if o, ok := g.(Overrider); ok {
+		opts.NameExtension, opts.TypeExtension = o.OverrideExtensions()
+	}
+	result, err := transform.Run(doc, target.Passes, opts)
+	if err != nil {
+		return nil, &SpecError{Err: err}
+	}
+	return result, nil
+}
+
+// generationHash returns the hash identifying the generation of the given target, from the given
+// document, by the given version of the generator e.g., "sha256:4f1c2a9be0d3c871"; it covers the
+// raw content of the files the document is parsed from, and the target's effective configuration.
+func generationHash(doc *parser.Document, target *config.Target, version string) (string, error) {
+	// The target's input and output don't affect the generated code; its passes are listed whether
+	// they're enabled explicitly or by default. Maps are encoded with sorted keys.
+	b, err := json.Marshal(struct {
+		Name      string
+		Client    config.Client
+		Overrides config.Overrides
+		Order     string
+		Passes    []string
+		Plugin    string
+		Extension string
+		Comment   string
+		Options   map[string]interface{}
+	}{
+		Name:      target.Name,
+		Client:    target.Client,
+		Overrides: target.Overrides,
+		Order:     target.Order,
+		Passes:    transform.Enabled(target.Passes),
+		Plugin:    target.Plugin,
+		Extension: target.Extension,
+		Comment:   target.Comment,
+		Options:   target.Options,
+	})
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(version + "\n"))
+	h.Write(append(b, '\n'))
+	// Each file is prefixed with its length, so that content can't shift from one file to another.
+	for _, src := range doc.Sources {
+		h.Write([]byte(fmt.Sprintf("%d\n", len(src))))
+		h.Write(src)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// generateTarget generates the code of the given target, from the given document, and writes it
+// to the file system opened by the given function; each file is prepended with the given watermark.
+func generateTarget(
+	target *config.Target, doc *parser.Document, watermark *output.Watermark, version string,
+	open func(target *config.Target) (output.FS, error), logger slog.Logger,
+) error {
+	g, err := newGenerator(target, version, logger)
+	if err != nil {
+		return err
+	}
+	if doc, err = transformDocument(doc, g, target); err != nil {
+		logger.Println("transformDocument:", err)
 		return err
 	}
 
-	files := (func() []*output.File {
-		switch extn {
-		case ".ts":
-			return typescript.Generate(doc, logger)
-		default:
-			return nil
+	files, err := g.Generate(doc)
+	if err != nil {
+		logger.Println(err)
+		var pluginErr *PluginError
+		if errors.As(err, &pluginErr) {
+			return err
 		}
-	})()
-	if err = output.CreateFiles(version, outDir, files, extn.String(), logger); err != nil {
+		return &SpecError{Err: err}
+	}
+	watermark.Style = g.CommentStyle()
+	fsys, err := open(target)
+	if err != nil {
 		logger.Println(err)
-		return err
+		return &WriteError{Err: err}
+	}
+	if err = output.WriteFiles(fsys, output.Render(files, g.Extension(), watermark), logger); err != nil {
+		logger.Println(err)
+		return &WriteError{Err: err}
+	}
+	if err = fsys.Close(); err != nil {
+		logger.Println(err)
+		return &WriteError{Err: err}
 	}
 
 	return nil
 }
-
-// Extension represents a file extension.
-type Extension string
-
-func (e Extension) String() string {
-	return string(e)
-}
-
-const (
-	ExtensionTypescript Extension = ".ts"
-)
@@ -0,0 +1,80 @@
+package gen
+
+import (
+	"sort"
+	"sync"
+
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/output"
+	"openapi-generator/internal/parser"
+	"openapi-generator/internal/slog"
+)
+
+// Generator represents the code generator of a target.
+type Generator interface {
+	// Name returns the target's name e.g., "typescript".
+	Name() string
+	// Extension returns the extension of the generated files e.g., ".ts".
+	Extension() string
+	// CommentStyle returns the style of the comments of the generated files, such as watermarks.
+	CommentStyle() output.CommentStyle
+	// Generate generates the files of the given document i.e., the target's own copy of the
+	// specification, as transformed by its passes (see `Transform`).
+	Generate(doc *parser.Document) ([]*output.File, error)
+}
+
+// Overrider is implemented by generators honouring the configured overrides, through
+// specification extensions.
+type Overrider interface {
+	// OverrideExtensions returns the extensions through which definitions and properties are named
+	// and typed e.g., "x-ts-name" and "x-ts-type".
+	OverrideExtensions() (name, typ string)
+}
+
+// Factory returns a new `Generator` for the given target.
+type Factory func(target *config.Target, logger slog.Logger) Generator
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes the generator returned by the given factory available under the given target
+// name; it panics if a generator is already registered under that name.
+//
+// Generators register themselves when their package is imported, for instance:
+//
+//	func init() {
+//		gen.Register("typescript", NewGenerator)
+//	}
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[name]; ok {
+		panic("gen: generator registered twice: " + name)
+	}
+	registry[name] = factory
+}
+
+// Targets returns the names of the registered generators, sorted alphabetically.
+func Targets() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupGenerator returns the factory of the generator registered under the given target name.
+func lookupGenerator(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[name]
+	return factory, ok
+}
@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/output"
+	"openapi-generator/internal/parser"
+	"openapi-generator/internal/slog"
+)
+
+// stubGenerator generates a single file, listing the document's definitions.
+type stubGenerator struct {
+	target *config.Target
+}
+
+func newStubGenerator(target *config.Target, _ slog.Logger) Generator {
+	return &stubGenerator{target: target}
+}
+
+func (g *stubGenerator) Name() string                      { return g.target.Name }
+func (g *stubGenerator) Extension() string                 { return ".txt" }
+func (g *stubGenerator) CommentStyle() output.CommentStyle { return output.CommentStyleHash }
+
+func (g *stubGenerator) Generate(doc *parser.Document) ([]*output.File, error) {
+	names := make([]string, 0, len(doc.Definitions))
+	for name := range doc.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return []*output.File{{Name: "definitions", Body: strings.Join(names, "\n") + "\n"}}, nil
+}
+
+// withRegistry replaces the registry with an empty one for the duration of the test.
+func withRegistry(t *testing.T) {
+	registryMu.Lock()
+	saved := registry
+	registry = make(map[string]Factory)
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+}
+
+func TestRegister(t *testing.T) {
+	withRegistry(t)
+	Register("stub", newStubGenerator)
+	Register("another", newStubGenerator)
+
+	if got, want := Targets(), []string{"another", "stub"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets() = %v, want %v", got, want)
+	}
+	if _, ok := lookupGenerator("stub"); !ok {
+		t.Errorf("lookupGenerator() didn't find 'stub'")
+	}
+	if _, err := newGenerator(&config.Target{Name: "cobol"}, "", slog.NewLogger("")); err == nil {
+		t.Errorf("newGenerator() with an unknown target didn't fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() twice under the same name didn't panic")
+		}
+	}()
+	Register("stub", newStubGenerator)
+}
+
+func TestGenerateRegisteredTarget(t *testing.T) {
+	withRegistry(t)
+	Register("stub", newStubGenerator)
+
+	doc, err := parser.NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member: {type: object, properties: {id: {type: string}}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := &config.Target{Name: "stub", Order: "sorted", Comment: "none"}
+	fsys := output.NewMemFS()
+	open := func(*config.Target) (output.FS, error) { return fsys, nil }
+	watermark := &output.Watermark{Message: "Generated."}
+	if err = generateTarget(target, doc, watermark, "1.0.0", open, slog.NewLogger("")); err != nil {
+		t.Fatalf("generateTarget() error = %v", err)
+	}
+
+	b, err := fsys.ReadFile("definitions.txt")
+	if err != nil {
+		t.Fatalf("the generated file wasn't written: %v", err)
+	}
+	if got := string(b); !strings.HasPrefix(got, "# Generated.") || !strings.HasSuffix(got, "Member\n") {
+		t.Errorf("definitions.txt = %q, want the hash-commented watermark, then the definitions", got)
+	}
+}
@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"openapi-generator/internal/parser"
+)
+
+// StdinInput is the input designating the standard input.
+const StdinInput = "-"
+
+var (
+	// The standard input is read once, as it may be shared by several targets.
+	stdinOnce    sync.Once
+	stdinContent []byte
+	stdinErr     error
+)
+
+// readStdin returns the content of the standard input.
+func readStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		stdinContent, stdinErr = io.ReadAll(os.Stdin)
+	})
+	return stdinContent, stdinErr
+}
+
+// LoadDocument parses the specification of the given input, which is either a file or
+// `StdinInput`; failures are returned as `SpecError`.
+func LoadDocument(input string) (*parser.Document, error) {
+	var (
+		doc *parser.Document
+		err error
+	)
+	if input == StdinInput {
+		var b []byte
+		if b, err = readStdin(); err == nil {
+			doc, err = parser.NewDocument(b)
+		}
+	} else {
+		doc, err = parser.NewDocumentFromFile(input)
+	}
+	if err != nil {
+		return nil, &SpecError{Err: err}
+	}
+	return doc, nil
+}
+
+// BundleDocument returns the specification of the given input as a single YAML document; failures
+// are returned as `SpecError`.
+func BundleDocument(input string) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if input == StdinInput {
+		if b, err = readStdin(); err == nil {
+			b, err = parser.Bundle(b)
+		}
+	} else {
+		b, err = parser.BundleFile(input)
+	}
+	if err != nil {
+		return nil, &SpecError{Err: err}
+	}
+	return b, nil
+}
@@ -0,0 +1,156 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"openapi-generator/internal"
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/output"
+	"openapi-generator/internal/parser"
+	"openapi-generator/internal/slog"
+)
+
+// PluginRequest represents the request written, as JSON, to the standard input of a plugin.
+type PluginRequest struct {
+	// The version of the program running the plugin.
+	Version string `json:"version"`
+	// The target to generate code for.
+	Target PluginTarget `json:"target"`
+	// The parsed specification; its fields are named after those of `parser.Document`.
+	Document *parser.Document `json:"document"`
+}
+
+// PluginTarget represents the options of the target a plugin generates code for.
+type PluginTarget struct {
+	// The target's name, as configured.
+	Name string `json:"name"`
+	// The generated API client.
+	Client PluginClient `json:"client"`
+	// The plugin's own options, as configured.
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// PluginClient represents the naming of the generated API client.
+type PluginClient struct {
+	Product  string `json:"product"`
+	Class    string `json:"class"`
+	Instance string `json:"instance"`
+}
+
+// PluginResponse represents the response read, as JSON, from the standard output of a plugin.
+type PluginResponse struct {
+	// The generated files; their directories are relative to the target's output directory, and
+	// their names are suffixed with the configured extension.
+	Files []*output.File `json:"files"`
+	// The reason the plugin failed to generate the files, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// pluginGenerator represents a generator implemented by an external executable, similarly to
+// protoc's plugins.
+type pluginGenerator struct {
+	target  *config.Target
+	version string
+	logger  slog.Logger
+}
+
+// newPluginGenerator returns a new `Generator` running the plugin of the given target.
+func newPluginGenerator(target *config.Target, version string, logger slog.Logger) Generator {
+	return &pluginGenerator{target: target, version: version, logger: logger}
+}
+
+func (g *pluginGenerator) Name() string {
+	return g.target.Name
+}
+
+func (g *pluginGenerator) Extension() string {
+	return g.target.Extension
+}
+
+// pluginNameExtensions are the extensions through which plugin targets' definitions and properties
+// are named, by the extension of their files.
+var pluginNameExtensions = map[string]string{
+	".go": internal.ExtensionGoName,
+}
+
+// OverrideExtensions names definitions and properties through the name extension of the language
+// of the plugin's files, if any; plugins' types can't be overridden.
+func (g *pluginGenerator) OverrideExtensions() (name, typ string) {
+	return pluginNameExtensions[g.target.Extension], ""
+}
+
+func (g *pluginGenerator) CommentStyle() output.CommentStyle {
+	style, _ := output.CommentStyleByName(g.target.Comment)
+	return style
+}
+
+// Generate runs the plugin, passing on the given document through its standard input, and returns
+// the files written to its standard output; the plugin's standard error is forwarded.
+func (g *pluginGenerator) Generate(doc *parser.Document) ([]*output.File, error) {
+	req, err := json.Marshal(&PluginRequest{
+		Version: g.version,
+		Target: PluginTarget{
+			Name:    g.target.Name,
+			Client:  PluginClient(g.target.Client),
+			Options: g.target.Options,
+		},
+		Document: doc,
+	})
+	if err != nil {
+		return nil, &PluginError{Err: fmt.Errorf("plugin '%s': failed to encode the request: %w", g.target.Plugin, err)}
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(g.target.Plugin)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	g.logger.Println("running plugin", g.target.Plugin)
+	if err = cmd.Run(); err != nil {
+		return nil, &PluginError{Err: fmt.Errorf("plugin '%s': %w", g.target.Plugin, err)}
+	}
+
+	var resp PluginResponse
+	dec := json.NewDecoder(&stdout)
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&resp); err != nil {
+		return nil, &PluginError{Err: fmt.Errorf("plugin '%s': invalid response: %w", g.target.Plugin, err)}
+	}
+	if resp.Error != "" {
+		return nil, &PluginError{Err: fmt.Errorf("plugin '%s': %s", g.target.Plugin, resp.Error)}
+	}
+	for i, f := range resp.Files {
+		if err = validatePluginFile(f, g.target.Extension); err != nil {
+			return nil, &PluginError{Err: fmt.Errorf("plugin '%s': files[%d]: %w", g.target.Plugin, i, err)}
+		}
+	}
+	return resp.Files, nil
+}
+
+// validatePluginFile checks that the given file, returned by a plugin, is named, is located within
+// the output directory, and isn't the manifest once suffixed with the given extension.
+func validatePluginFile(f *output.File, extn string) error {
+	if f == nil || f.Name == "" {
+		return fmt.Errorf("a name must be specified")
+	}
+	if strings.ContainsAny(f.Name, `/\`) {
+		return fmt.Errorf("name '%s' must not contain a separator", f.Name)
+	}
+	if f.Directory != "" {
+		dir := filepath.Clean(filepath.FromSlash(f.Directory))
+		if filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("directory '%s' must be relative to the output directory", f.Directory)
+		}
+	}
+	if p := path.Join(filepath.ToSlash(f.Directory), f.Name+extn); p == output.ManifestName {
+		return fmt.Errorf("name '%s' is reserved for the manifest", p)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package gen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/output"
+	"openapi-generator/internal/parser"
+	"openapi-generator/internal/slog"
+)
+
+func TestValidatePluginFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    *output.File
+		wantErr bool
+	}{
+		{name: "valid", file: &output.File{Name: "models", Directory: "acme"}},
+		{name: "unnamed", file: &output.File{Directory: "acme"}, wantErr: true},
+		{name: "separator", file: &output.File{Name: "acme/models"}, wantErr: true},
+		{name: "outside", file: &output.File{Name: "models", Directory: "../acme"}, wantErr: true},
+		{name: "manifest", file: &output.File{Name: ".openapi-parser-manifest", Directory: "."}, wantErr: true},
+		{name: "nested manifest", file: &output.File{Name: ".openapi-parser-manifest", Directory: "acme"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePluginFile(tt.file, ".json"); (err != nil) != tt.wantErr {
+				t.Errorf("validatePluginFile() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPluginTransformGoNames(t *testing.T) {
+	doc, err := parser.NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member:
+    type: object
+    x-go-name: Person
+    x-ts-name: Human
+    properties:
+      member_id: {type: string, x-go-name: ID}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := &config.Target{Name: "go", Plugin: "protoc-gen-acme", Extension: ".go"}
+	result, err := Transform(doc, target)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	def, ok := result.Definitions["Person"]
+	if !ok {
+		t.Fatal("Transform() didn't rename Member as per x-go-name")
+	}
+	if prop := def.Properties[0]; prop.Key != "member_id" || prop.Alias != "ID" {
+		t.Errorf("property key, alias = %q, %q; want member_id, ID", prop.Key, prop.Alias)
+	}
+}
+
+// writePlugin writes a shell script plugin printing the given response, and returns its path; the
+// request is copied to "request.json", alongside it.
+func writePlugin(t *testing.T, response string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "plugin.sh")
+	script := "#!/bin/sh\ncat > '" + filepath.Join(dir, "request.json") + "'\nprintf '%s\\n' '" + response + "'\n"
+	if err := os.WriteFile(plugin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return plugin
+}
+
+func TestPluginGenerate(t *testing.T) {
+	doc, err := parser.NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member: {type: object, properties: {id: {type: string}}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("files", func(t *testing.T) {
+		plugin := writePlugin(t, `{"files": [{"name": "models", "directory": "acme", "body": "class Member: pass\n"}]}`)
+		target := &config.Target{
+			Name:      "python",
+			Plugin:    plugin,
+			Extension: ".py",
+			Comment:   "hash",
+			Options:   map[string]interface{}{"package": "acme"},
+		}
+		fsys := output.NewMemFS()
+		open := func(*config.Target) (output.FS, error) { return fsys, nil }
+		watermark := &output.Watermark{Message: "Generated."}
+		if err := generateTarget(target, doc, watermark, "1.0.0", open, slog.NewLogger("")); err != nil {
+			t.Fatalf("generateTarget() error = %v", err)
+		}
+
+		b, err := fsys.ReadFile("acme/models.py")
+		if err != nil {
+			t.Fatalf("the plugin's file wasn't written: %v", err)
+		}
+		if got := string(b); !strings.HasPrefix(got, "# Generated.") || !strings.HasSuffix(got, "class Member: pass\n") {
+			t.Errorf("acme/models.py = %q, want the hash-commented watermark, then the body", got)
+		}
+		req, err := os.ReadFile(filepath.Join(filepath.Dir(plugin), "request.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"version":"1.0.0"`, `"name":"python"`, `"package":"acme"`, `"Member"`} {
+			if !strings.Contains(string(req), want) {
+				t.Errorf("request = %s, want it to hold %s", req, want)
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		target := &config.Target{Name: "python", Plugin: writePlugin(t, `{"error": "unsupported"}`), Extension: ".py"}
+		_, err := newPluginGenerator(target, "1.0.0", slog.NewLogger("")).Generate(doc)
+		var pluginErr *PluginError
+		if !errors.As(err, &pluginErr) || !strings.Contains(err.Error(), "unsupported") {
+			t.Errorf("Generate() error = %v, want a PluginError holding the plugin's error", err)
+		}
+	})
+}
@@ -13,21 +13,38 @@ import (
 	"openapi-generator/internal"
 )
 
-// generateClass generates a typescript class from the given definition.
-func generateClass(def *parser.Definition) string {
+// generateClass generates a typescript class from the given definition, whose properties are
+// listed as per the given order.
+func generateClass(defs map[string]*parser.Definition, def *parser.Definition, order internal.Order) string {
 	template := templates.Class
 
 	// Class description.
 	if defDesc := def.Description; defDesc != "" {
 		template = toJSDoc("", defDesc) + template
 	}
+	// Class's parent; a class extends a single parent, the others' properties are copied over.
+	extends := ""
+	props := def.Properties
+	if len(def.Extends) > 0 {
+		extends = " extends " + def.Extends[0]
+		props = append(inheritedProperties(defs, def), props...)
+	}
 	// Class properties.
-	mappedProps := make([]string, 0, len(def.Properties))
+	mappedProps := make([]string, 0, len(props))
 	// Class constructor properties.
-	mappedConstructorProps := make([]string, 0, len(def.Properties))
-	for _, prop := range internal.SortProperties(def.Properties) {
-		mappedProps = append(mappedProps, generateObjectProperty(def.Key, "", prop))
-		mappedConstructorProps = append(mappedConstructorProps, generateClassConstructorProperty(def.Key, prop))
+	mappedConstructorProps := make([]string, 0, len(props)+1)
+	if extends != "" {
+		mappedConstructorProps = append(mappedConstructorProps, "\t\tsuper(data);")
+	}
+	// Maps' values are copied over first, as their declared properties are constructed afterwards.
+	if def.AdditionalProperties != nil {
+		mappedProps = append(mappedProps, fmt.Sprintf(templates.ObjectIndexSignature, generateIndexSignatureType(defs, def, props)))
+		mappedConstructorProps = append(mappedConstructorProps,
+			fmt.Sprintf("\t\tObject.assign(this, %s);", generateMapConstruction(defs, def.AdditionalProperties, "data")))
+	}
+	for _, prop := range order.Properties(props) {
+		mappedProps = append(mappedProps, generateObjectProperty(def.Key, "", classPropertyName(prop), prop))
+		mappedConstructorProps = append(mappedConstructorProps, generateClassConstructorProperty(defs, prop))
 	}
 	// Class methods if any.
 	classMethods := ""
@@ -36,20 +53,118 @@ func generateClass(def *parser.Definition) string {
 		classMethods = constants.AddressClassMethods
 	}
 
-	return fmt.Sprintf(template,
+	result := fmt.Sprintf(template,
 		def.Key,
+		extends,
 		strings.Join(mappedProps, "\n"),
 		strings.Join(mappedConstructorProps, "\n"),
 		classMethods,
 	)
+	// Discriminated classes come with narrowing helpers and a factory.
+	if helpers := generateDiscriminatorHelpers(defs, def); helpers != "" {
+		result += "\n\n" + helpers
+	}
+	return result
+}
+
+// inheritedProperties returns the properties of the given definition's parents, but the first.
+func inheritedProperties(defs map[string]*parser.Definition, def *parser.Definition) []*parser.DefinitionProperty {
+	declared := make(map[string]bool, len(def.Properties))
+	for _, prop := range def.Properties {
+		declared[prop.Key] = true
+	}
+	props := make([]*parser.DefinitionProperty, 0)
+	for _, parent := range def.Extends[1:] {
+		parentDef, ok := defs[parent]
+		if !ok {
+			continue
+		}
+		for _, prop := range parentDef.Properties {
+			if !declared[prop.Key] {
+				declared[prop.Key] = true
+				props = append(props, prop)
+			}
+		}
+	}
+	return props
+}
+
+// generateIndexSignatureType generates the type of the index signature of the given map class,
+// declaring the given properties; its values must accept the types of every property of the class,
+// inherited ones included.
+func generateIndexSignatureType(defs map[string]*parser.Definition, def *parser.Definition, props []*parser.DefinitionProperty) string {
+	// Parents' properties, the first one's included, are gathered recursively.
+	visited := map[string]bool{def.Key: true}
+	var gather func(parents []string)
+	gather = func(parents []string) {
+		for _, parent := range parents {
+			parentDef, ok := defs[parent]
+			if !ok || visited[parent] {
+				continue
+			}
+			visited[parent] = true
+			props = append(props, parentDef.Properties...)
+			gather(parentDef.Extends)
+		}
+	}
+	gather(def.Extends)
+
+	types := []string{generatePropertyType(def.Key, "", def.AdditionalProperties)}
+	seen := map[string]bool{types[0]: true}
+	for _, prop := range props {
+		t := generatePropertyType(def.Key, "", prop)
+		if !prop.Required {
+			t += " | undefined"
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return strings.Join(types, " | ")
+}
+
+// generateMap generates a typescript record type from the given map definition.
+func generateMap(def *parser.Definition) string {
+	template := templates.Map
+
+	// Map description.
+	if defDesc := def.Description; defDesc != "" {
+		template = toJSDoc("", defDesc) + template
+	}
+	return fmt.Sprintf(template, def.Key, generatePropertyType(def.Key, "", def.AdditionalProperties))
+}
+
+// mapValues returns the values of the map held by the given property, be it a map itself or a
+// reference to a map definition; nil if it holds none.
+func mapValues(defs map[string]*parser.Definition, prop *parser.DefinitionProperty) *parser.DefinitionProperty {
+	if prop.AdditionalProperties != nil {
+		return prop.AdditionalProperties
+	}
+	if def, ok := defs[prop.Ref]; ok && def.Type == "map" && prop.Type != "array" {
+		return def.AdditionalProperties
+	}
+	return nil
 }
 
 // generateClassRequest generates a typescript request class for the given definition.
-func generateClassRequest(path *parser.Path) string {
+func generateClassRequest(path *parser.Path, reqBodies map[string]*parser.Definition) string {
+	// Form payloads are described by their parameters.
+	if len(internal.FilterParameters(path.Parameters, "formData")) > 0 {
+		return generateInterface(&parser.Definition{
+			Key:        strcase.ToCamel(path.Operation) + "Request",
+			Properties: internal.FilterParameters(path.Parameters, "formData"),
+		}, "m.")
+	}
+
 	extends := ""
-	for _, prop := range internal.SortProperties(path.Parameters) {
-		if prop.Key == "Body" {
+	for _, prop := range internal.FilterParameters(path.Parameters, "body") {
+		switch _, ok := reqBodies[prop.Ref]; {
+		case ok:
 			extends = " extends " + strcase.ToLowerCamel(prop.Ref)
+		case prop.Ref != "":
+			// Request bodies which aren't request body definitions are imported from './models'.
+			extends = " extends m." + prop.Ref
 		}
 	}
 	return fmt.Sprintf(templates.Request,
@@ -58,14 +173,31 @@ func generateClassRequest(path *parser.Path) string {
 	)
 }
 
+// generateRequestParams generates a typescript interface from the given operation's parameters
+// with the given destination e.g., its query parameters.
+func generateRequestParams(path *parser.Path, in, suffix string) string {
+	params := internal.FilterParameters(path.Parameters, in)
+	if len(params) == 0 {
+		return ""
+	}
+	return generateInterface(&parser.Definition{
+		Key:        strcase.ToCamel(path.Operation) + suffix,
+		Properties: params,
+	}, "m.")
+}
+
 // generateClassResponse generates a typescript response class for the given definition.
-func generateClassResponse(def *parser.Definition) string {
-	// Class's extend type.
+func generateClassResponse(defs map[string]*parser.Definition, def *parser.Definition) string {
+	// Class's extend type; response bodies are declared alongside responses, whereas other
+	// definitions are imported from './models'.
 	extends := def.Ref
+	if _, ok := defs[def.Ref]; !ok {
+		extends = "m." + def.Ref
+	}
 	if !internal.IsErrorType(def.Key) {
 		if extendsType := def.Returns; extendsType != "" {
 			if internal.IsPrimitiveType(extendsType) {
-				extends += "<" + extendsType + ">"
+				extends += "<" + toTSType(extendsType) + ">"
 			} else {
 				extends += "<m." + extendsType + ">"
 			}
@@ -93,7 +225,7 @@ func generateClassResponseBody(def *parser.Definition) string {
 			template = templates.ResponseErrorBody
 		} else {
 			className += "<T>"
-			if internal.IsPaginatedResponse(def.Key) {
+			if internal.IsPaginatedResponse(def) {
 				classExtends = "PaginatedResponse<T>"
 			} else {
 				classExtends = "SuccessResponse<T>"
@@ -124,8 +256,9 @@ func setConstructorSuperArgs(def *parser.Definition) string {
 	}
 }
 
-// generateObjectProperty generates a typescript object property from the given definition.
-func generateObjectProperty(pKey, prefix string, prop *parser.DefinitionProperty) string {
+// generateObjectProperty generates a typescript object property, declared under the given name,
+// from the given definition.
+func generateObjectProperty(pKey, prefix, name string, prop *parser.DefinitionProperty) string {
 	template := templates.ObjectProperty
 
 	// Property's description.
@@ -135,8 +268,8 @@ func generateObjectProperty(pKey, prefix string, prop *parser.DefinitionProperty
 	if propDesc == "" {
 		propDesc = generateObjectPropertyMissingComment(prop.Key)
 	}
-	if propDesc != "" {
-		template = toJSDoc("\t", propDesc) + template
+	if propDesc != "" || len(prop.Examples) > 0 {
+		template = toJSDocWithExamples("\t", propDesc, prop.Examples) + template
 	}
 
 	// Property's required flag.
@@ -144,12 +277,49 @@ func generateObjectProperty(pKey, prefix string, prop *parser.DefinitionProperty
 	if propRequired := prop.Required; !propRequired {
 		requiredFlag = "?"
 	}
-	// Property's type.
+
+	return fmt.Sprintf(template, toTSPropertyKey(name), requiredFlag, generatePropertyType(pKey, prefix, prop))
+}
+
+// classPropertyName returns the name of the given property within classes i.e., its alias if any,
+// and its key otherwise; objects sent over the wire are keyed by the latter.
+func classPropertyName(prop *parser.DefinitionProperty) string {
+	if prop.Alias != "" {
+		return prop.Alias
+	}
+	return prop.Key
+}
+
+// generatePropertyType generates the typescript type of the given property.
+func generatePropertyType(pKey, prefix string, prop *parser.DefinitionProperty) string {
 	propType := prop.Type
 	switch {
+	case prop.Extensions.String(internal.ExtensionTSType) != "":
+		propType = prop.Extensions.String(internal.ExtensionTSType)
+	case len(prop.PrefixItems) > 0:
+		items := make([]string, 0, len(prop.PrefixItems))
+		for _, item := range prop.PrefixItems {
+			items = append(items, generatePropertyType(pKey, prefix, item))
+		}
+		propType = "[" + strings.Join(items, ", ") + "]"
+	case prop.AdditionalProperties != nil:
+		propType = "Record<string, " + generatePropertyType(pKey, prefix, prop.AdditionalProperties) + ">"
+	case internal.IsBinaryProperty(prop):
+		propType = "File | Blob"
+	case prop.Const != nil:
+		propType = toTSLiteral(prop.Const)
+	case len(prop.Union) > 1:
+		types := make([]string, 0, len(prop.Union))
+		for _, t := range prop.Union {
+			types = append(types, toTSType(t))
+		}
+		propType = strings.Join(types, " | ")
 	// Account for enums imported from './enums'.
-	case !internal.HasConstructor(prop.Ref):
+	case !internal.HasConstructor(prop):
 		propType = "e." + prop.Ref
+		if prop.Type == "array" {
+			propType += "[]"
+		}
 	case prop.Ref != "" && prop.Type != "array":
 		if strings.Contains(pKey, "DynamicQuery") && strings.Contains(prop.Ref, "DynamicQuery") {
 			propType = prop.Ref
@@ -158,18 +328,21 @@ func generateObjectProperty(pKey, prefix string, prop *parser.DefinitionProperty
 		}
 	default:
 		switch prop.Type {
-		case "integer":
-			propType = "number"
 		case "array":
 			if internal.IsPrimitiveType(prop.Ref) {
-				propType = prop.Ref + "[]"
+				propType = toTSType(prop.Ref) + "[]"
 			} else {
 				propType = prefix + prop.Ref + "[]"
 			}
+		default:
+			propType = toTSType(prop.Type)
 		}
 	}
-
-	return fmt.Sprintf(template, prop.Key, requiredFlag, propType)
+	// The null type is nullable already.
+	if prop.Nullable && propType != "null" {
+		propType += " | null"
+	}
+	return propType
 }
 
 // generateObjectPropertyMissingComment generates a comment for the given property.
@@ -198,25 +371,112 @@ func generateObjectPropertyMissingComment(key string) string {
 
 // generateClassConstructorProperty generates a typescript class constructor property from the given
 // definition.
-func generateClassConstructorProperty(key string, prop *parser.DefinitionProperty) (result string) {
+func generateClassConstructorProperty(defs map[string]*parser.Definition, prop *parser.DefinitionProperty) string {
+	return fmt.Sprintf("\t\tthis%s = %s;", toTSPropertyAccessor(classPropertyName(prop)), generateClassConstructorValue(defs, prop))
+}
+
+// generateClassConstructorValue generates the typescript expression constructing the given property
+// from the constructor's data, in which it's keyed as sent over the wire.
+func generateClassConstructorValue(defs map[string]*parser.Definition, prop *parser.DefinitionProperty) string {
+	v := "data" + toTSPropertyAccessor(prop.Key)
+	nullCheck := ""
+	if !prop.Required {
+		nullCheck = "?"
+	}
 	switch {
-	case strings.HasSuffix(prop.Key, "_at") && !strings.Contains(key, "DynamicQuery"):
-		return fmt.Sprintf("\t\tthis.%[1]s = new ExtendedDate(data.%[1]s);", prop.Key)
-	case prop.Key == "country_code":
-		return fmt.Sprintf("\t\tthis.%[1]s = new Country(data.%[1]s);", prop.Key, prop.Type)
-	case internal.HasConstructor(prop.Ref) && prop.Type == "":
-		return fmt.Sprintf("\t\tthis.%[1]s = new %s(data.%[1]s);", prop.Key, prop.Ref)
-	case !internal.HasConstructor(prop.Ref) && prop.Type == "":
-		return fmt.Sprintf("\t\tthis.%[1]s = data.%[1]s as e.%[2]s;", prop.Key, prop.Ref)
-	case prop.Ref != "" && prop.Type == "array":
-		nullCheck := ""
-		if !prop.Required {
-			nullCheck = "?"
+	// Maps are constructed value by value, when their values are models.
+	case mapValues(defs, prop) != nil:
+		construction := generateMapConstruction(defs, mapValues(defs, prop), v)
+		if construction != v && (!prop.Required || prop.Nullable) {
+			return fmt.Sprintf("%[1]s == null ? %[1]s : %[2]s", v, construction)
 		}
-		return fmt.Sprintf("\t\tthis.%[1]s = data.%[1]s%[2]s.map((e: any) => new %[3]s(e));", prop.Key, nullCheck, prop.Ref)
+		return construction
+	// Polymorphic models are constructed through their factory, when they have one.
+	case isPolymorphic(defs[prop.Ref]):
+		switch {
+		case defs[prop.Ref].Discriminator == nil:
+			return v
+		case prop.Type == "array":
+			return fmt.Sprintf("%s%s.map((e: any) => new%s(e))", v, nullCheck, prop.Ref)
+		case !prop.Required:
+			return fmt.Sprintf("%[1]s == null ? undefined : new%[2]s(%[1]s)", v, prop.Ref)
+		default:
+			return fmt.Sprintf("new%s(%s)", prop.Ref, v)
+		}
+	// Properties whose type is overridden are constructed as such when it's a class e.g.,
+	// `ExtendedDate`, and cast otherwise e.g., `string | null`.
+	case prop.Extensions.String(internal.ExtensionTSType) != "":
+		t := prop.Extensions.String(internal.ExtensionTSType)
+		if def, ok := defs[t]; constructedTypes[t] || ok && def.Type == "object" {
+			return fmt.Sprintf("new %s(%s)", t, v)
+		}
+		return fmt.Sprintf("%s as %s", v, t)
+	case prop.Nullable && prop.Ref != "" && internal.HasConstructor(prop) && prop.Type != "array":
+		return fmt.Sprintf("%[1]s === null ? null : new %[2]s(%[1]s)", v, prop.Ref)
+	case internal.HasConstructor(prop) && prop.Type == "":
+		return fmt.Sprintf("new %s(%s)", prop.Ref, v)
+	case !internal.HasConstructor(prop) && prop.Type == "":
+		return fmt.Sprintf("%s as e.%s", v, prop.Ref)
+	case !internal.HasConstructor(prop) && prop.Type == "array":
+		return fmt.Sprintf("%s as e.%s[]", v, prop.Ref)
+	// Collections of maps are constructed map by map, when their values are models.
+	case prop.Type == "array" && defs[prop.Ref] != nil && defs[prop.Ref].Type == "map":
+		if construction := generateValueConstruction(defs, prop, v+nullCheck); construction != v+nullCheck {
+			return construction
+		}
+		return v
+	case prop.Ref != "" && prop.Type == "array":
+		return fmt.Sprintf("%s%s.map((e: any) => new %s(e))", v, nullCheck, prop.Ref)
 	case prop.Ref != "":
-		return fmt.Sprintf("\t\tthis.%[1]s = new %[2]s(data.%[1]s);", prop.Key, prop.Ref)
+		return fmt.Sprintf("new %s(%s)", prop.Ref, v)
 	default:
-		return fmt.Sprintf("\t\tthis.%[1]s = data.%[1]s;", prop.Key)
+		return v
+	}
+}
+
+// constructedTypes are the classes declared alongside the models, which properties' types may be
+// overridden with (see "x-ts-type").
+var constructedTypes = map[string]bool{
+	"Country":      true,
+	"ExtendedDate": true,
+}
+
+// generateMapConstruction generates the typescript expression constructing the map of the given
+// values from the given variable; the variable is returned as is when the values aren't models.
+func generateMapConstruction(defs map[string]*parser.Definition, values *parser.DefinitionProperty, v string) string {
+	value := generateValueConstruction(defs, values, "v")
+	if value == "v" {
+		return v
+	}
+	return fmt.Sprintf("Object.fromEntries(Object.entries(%s).map(([k, v]: [string, any]) => [k, %s]))", v, value)
+}
+
+// generateValueConstruction generates the typescript expression constructing the given value from
+// the given variable; the variable is returned as is when the value isn't a model.
+func generateValueConstruction(defs map[string]*parser.Definition, value *parser.DefinitionProperty, v string) string {
+	def, ok := defs[value.Ref]
+	if !ok || !internal.HasConstructor(value) {
+		return v
+	}
+	// Maps are constructed value by value.
+	if def.Type == "map" {
+		if value.Type != "array" {
+			return generateMapConstruction(defs, def.AdditionalProperties, v)
+		}
+		if construction := generateMapConstruction(defs, def.AdditionalProperties, "e"); construction != "e" {
+			return fmt.Sprintf("%s.map((e: any) => %s)", v, construction)
+		}
+		return v
+	}
+	factory := "new " + value.Ref
+	if isPolymorphic(def) {
+		if def.Discriminator == nil {
+			return v
+		}
+		factory = "new" + value.Ref
+	}
+	if value.Type == "array" {
+		return fmt.Sprintf("%s.map((e: any) => %s(e))", v, factory)
 	}
+	return fmt.Sprintf("%s(%s)", factory, v)
 }
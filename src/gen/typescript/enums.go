@@ -6,6 +6,7 @@ import (
 
 	"github.com/iancoleman/strcase"
 
+	"openapi-generator/internal"
 	"openapi-generator/internal/parser"
 
 	"openapi-generator/gen/typescript/templates"
@@ -19,58 +20,28 @@ func generateEnum(def *parser.Definition) string {
 	if defDesc := def.Description; defDesc != "" {
 		template = toJSDoc("", defDesc) + template
 	}
-	// Enum entries.
+	// Enum entries; entries are named after their value unless named through the "x-enum-varnames"
+	// extension.
+	varNames := def.Extensions.Strings(internal.ExtensionEnumVarNames)
+	entryTemplate := templates.EnumStringProperty
+	if def.EnumType == "integer" || def.EnumType == "number" {
+		entryTemplate = templates.EnumNumberProperty
+	}
 	mappedEntries := make([]string, 0, len(def.EnumEntries))
-	for _, entry := range def.EnumEntries {
+	for i, entry := range def.EnumEntries {
 		if entry == "" {
 			continue
 		}
-		switch def.Key {
-		case "Colour":
-			mappedEntries = append(mappedEntries, generateEnumColourProperty(entry))
-		default:
-			mappedEntries = append(mappedEntries,
-				fmt.Sprintf(templates.EnumStringProperty, strcase.ToScreamingSnake(entry), entry))
+		name := strcase.ToScreamingSnake(entry)
+		if i < len(varNames) && varNames[i] != "" {
+			name = varNames[i]
+		}
+		// Identifiers can't start with a digit e.g., entries of integer enumerations.
+		if name != "" && name[0] >= '0' && name[0] <= '9' {
+			name = "_" + name
 		}
+		mappedEntries = append(mappedEntries, fmt.Sprintf(entryTemplate, name, entry))
 	}
 
 	return fmt.Sprintf(template, def.Key, strings.Join(mappedEntries, "\n"))
 }
-
-// generateEnumColourProperty generates a typescript enum property for the given colour.
-func generateEnumColourProperty(entry string) string {
-	colourName := ""
-	switch entry {
-	case "1":
-		colourName = "AMBER"
-	case "2":
-		colourName = "ORANGE"
-	case "3":
-		colourName = "TOMATO"
-	case "4":
-		colourName = "RED"
-	case "5":
-		colourName = "CRIMSON"
-	case "6":
-		colourName = "PINK"
-	case "7":
-		colourName = "PLUM"
-	case "8":
-		colourName = "PURPLE"
-	case "9":
-		colourName = "VIOLET"
-	case "10":
-		colourName = "INDIGO"
-	case "11":
-		colourName = "BLUE"
-	case "12":
-		colourName = "CYAN"
-	case "13":
-		colourName = "TEAL"
-	case "14":
-		colourName = "GREEN"
-	case "15":
-		colourName = "GRASS"
-	}
-	return fmt.Sprintf(templates.EnumNumberProperty, colourName, entry)
-}
@@ -6,6 +6,7 @@ import (
 	"openapi-generator/gen/typescript/constants"
 	"openapi-generator/gen/typescript/templates"
 	"openapi-generator/internal"
+	"openapi-generator/internal/config"
 	"openapi-generator/internal/parser"
 	"openapi-generator/internal/slog"
 	"regexp"
@@ -14,15 +15,18 @@ import (
 
 var dynanicQueryFilterRegex = regexp.MustCompile(`[aA-zZ]+DynamicQueryFilter[A-Z][aA-zZ]+`)
 
-// generateModelTypes generates typescript types from the given definitions.
-func generateModelTypes(defs map[string]*parser.Definition, logger slog.Logger) string {
+// generateModelTypes generates typescript types from the given definitions; the given enumerations
+// are imported from './enums'.
+func generateModelTypes(
+	defs, enums map[string]*parser.Definition, order internal.Order, logger slog.Logger,
+) string {
 	mappedDefs := []string{
 		constants.ModelsImports,
 		constants.ExtendedDate,
 		constants.DynamicQueryFilterGeneric,
 	}
-	for _, k := range internal.SortMapKeysAlphabetically(defs) {
-		def := internal.OverrideDefinition(defs[k])
+	for _, k := range internal.SortKeysByInheritance(internal.SortMapKeysAlphabetically(defs), defs) {
+		def := defs[k]
 		logger.Printf("saw '%s'", def.Key)
 
 		resultType := ""
@@ -32,10 +36,14 @@ func generateModelTypes(defs map[string]*parser.Definition, logger slog.Logger)
 			continue
 		case strings.HasSuffix(k, "DynamicQueryFilters"):
 			resultType = generateDynamicQueryFilters(defs, def)
+		case def.Type == "union":
+			resultType = generateUnion(defs, def, enums)
+		case def.Type == "map":
+			resultType = generateMap(def)
 		case isInterface(def.Key) || strings.HasSuffix(def.Key, "Data"):
-			resultType = generateInterface(def)
+			resultType = generateInterface(def, "")
 		default:
-			resultType = generateClass(def)
+			resultType = generateClass(defs, def, order)
 		}
 
 		logger.Printf("generated '%s'", def.Key)
@@ -51,7 +59,7 @@ func generateEnumTypes(defs map[string]*parser.Definition, logger slog.Logger) s
 	sliceLen := len(defs) + 1
 	mappedEnums := make([]string, 0, sliceLen)
 	for _, k := range internal.SortMapKeysAlphabetically(defs) {
-		def := internal.OverrideDefinition(defs[k])
+		def := defs[k]
 		logger.Printf("saw enum '%s'", def.Key)
 
 		mappedEnums = append(mappedEnums, generateEnum(def))
@@ -78,7 +86,7 @@ func generateResponseTypes(defs map[string]*parser.Definition, logger slog.Logge
 		if strings.HasSuffix(def.Key, "Body") {
 			mappedDefs = append(mappedDefs, generateClassResponseBody(def))
 		} else {
-			mappedDefs = append(mappedDefs, generateClassResponse(def))
+			mappedDefs = append(mappedDefs, generateClassResponse(defs, def))
 		}
 		logger.Printf("generated response '%s'", def.Key)
 	}
@@ -87,24 +95,34 @@ func generateResponseTypes(defs map[string]*parser.Definition, logger slog.Logge
 	return strings.Join(mappedDefs, "\n\n")
 }
 
-// generateRequestTypes generates typescript types from the given paths.
-func generateRequestTypes(defs map[string]*parser.Path, reqBodies map[string]*parser.Definition, logger slog.Logger) string {
+// generateRequestTypes generates typescript types from the given paths, listed as per the given
+// order.
+func generateRequestTypes(
+	defs map[string]*parser.Path, reqBodies map[string]*parser.Definition, order internal.Order, logger slog.Logger,
+) string {
 	mappedDefs := make([]string, 0, len(defs)+1)
 	mappedDefs = append(mappedDefs, constants.RequestsImports)
-	for _, def := range reqBodies {
-		def = internal.OverrideDefinition(def)
+	for _, k := range internal.SortMapKeysAlphabetically(reqBodies) {
+		// Request bodies are shared with other workers; they're renamed on a copy.
+		def := *reqBodies[k]
 		def.Key = strcase.ToLowerCamel(def.Key)
-		mappedDefs = append(mappedDefs, generateInterface(def))
+		mappedDefs = append(mappedDefs, generateInterface(&def, "m."))
 	}
-	for _, paths := range internal.MapByPkg(defs) {
-		for _, path := range paths {
-			logger.Printf("saw path '%s'", path.Key)
-			if !internal.IsSuitedForAPIMethod(path.Parameters) {
-				continue
+	for _, path := range order.Paths(defs) {
+		logger.Printf("saw path '%s'", path.Key)
+		for _, params := range []string{
+			generateRequestParams(path, "query", "Query"),
+			generateRequestParams(path, "header", "Headers"),
+		} {
+			if params != "" {
+				mappedDefs = append(mappedDefs, params)
 			}
-			mappedDefs = append(mappedDefs, generateClassRequest(path))
-			logger.Printf("generated path '%s'", path.Key)
 		}
+		if !internal.IsSuitedForAPIMethod(path.Parameters) {
+			continue
+		}
+		mappedDefs = append(mappedDefs, generateClassRequest(path, reqBodies))
+		logger.Printf("generated path '%s'", path.Key)
 	}
 	logger.Printf("[generateRequestTypes] received %d :: mapped %d", len(defs), len(mappedDefs)-1)
 
@@ -112,18 +130,20 @@ func generateRequestTypes(defs map[string]*parser.Path, reqBodies map[string]*pa
 }
 
 // generateRequestValidationObjects generates typescript validation objects from the given
-// validation properties.
+// validation properties, listed as per the given order; nested objects are looked up from the
+// given definitions.
 func generateRequestValidationObjects(
-	defs map[string][]*parser.DefinitionProperty, logger slog.Logger,
+	defs map[string]*parser.Definition, validations map[string][]*parser.DefinitionProperty, order internal.Order, logger slog.Logger,
 ) string {
-	mappedObjects := make([]string, 0, len(defs)+1)
+	mappedObjects := make([]string, 0, len(validations)+1)
 	mappedObjects = append(mappedObjects, constants.ValidationImports)
-	for k, v := range defs {
+	for _, k := range internal.SortMapKeysAlphabetically(validations) {
+		v := validations[k]
 		logger.Printf("saw validation object '%s'", k)
-		mappedObjects = append(mappedObjects, generateRequestClassValidationObject(k, v))
+		mappedObjects = append(mappedObjects, generateRequestClassValidationObject(defs, k, v, order))
 		logger.Printf("generated validation object '%s'", k)
 	}
-	logger.Printf("[generateRequestValidationObjects] received %d :: mapped %d", len(defs), len(mappedObjects)-1)
+	logger.Printf("[generateRequestValidationObjects] received %d :: mapped %d", len(validations), len(mappedObjects)-1)
 
 	return strings.Join(mappedObjects, "\n\n")
 }
@@ -133,18 +153,21 @@ func generateRestClient(host, basePath string, _ slog.Logger) string {
 	return fmt.Sprintf(templates.RestClient, host, basePath)
 }
 
-// generateAPIClient generates the API client code for the given spec.
-func generateAPIClient(defs map[string]*parser.Path, logger slog.Logger) string {
+// generateAPIClient generates the API client code for the given spec, named as per the given
+// client configuration; methods are listed as per the given order.
+func generateAPIClient(defs map[string]*parser.Path, client config.Client, order internal.Order, logger slog.Logger) string {
 	// The client's methods.
 	mappedMethods := make([]string, 0, len(defs))
-	for _, paths := range internal.MapByPkg(defs) {
-		for _, path := range paths {
-			logger.Printf("saw method '%s'", path.Key)
-			mappedMethods = append(mappedMethods, generateAPIMethod(path))
-			logger.Printf("generated method '%s'", path.Key)
-		}
+	for _, path := range order.Paths(defs) {
+		logger.Printf("saw method '%s'", path.Key)
+		mappedMethods = append(mappedMethods, generateAPIMethod(path))
+		logger.Printf("generated method '%s'", path.Key)
 	}
 	logger.Printf("[generateAPIClient] received %d :: mapped %d", len(defs), len(mappedMethods))
 
-	return fmt.Sprintf(templates.APIClient, strings.Join(mappedMethods, "\n\n"))
+	product := ""
+	if client.Product != "" {
+		product = client.Product + " "
+	}
+	return fmt.Sprintf(templates.APIClient, client.Class, product, strings.Join(mappedMethods, "\n\n"), client.Instance)
 }
@@ -18,10 +18,11 @@ func isInterface(key string) bool {
 	return false
 }
 
-// generateInterface generates a typescript interface from the given definition.
-func generateInterface(def *parser.Definition) string {
+// generateInterface generates a typescript interface from the given definition; referenced
+// models are prefixed with the given prefix.
+func generateInterface(def *parser.Definition, prefix string) string {
 	template := templates.Interface
-	if strings.Contains(def.Key, "RequestBody") {
+	if strings.HasSuffix(def.Key, "RequestBody") {
 		template = templates.RequestBody
 	}
 
@@ -29,19 +30,33 @@ func generateInterface(def *parser.Definition) string {
 	if defDesc := def.Description; defDesc != "" {
 		template = toJSDoc("", defDesc) + template
 	}
+	// Interface's parents.
+	extends := ""
+	if len(def.Extends) > 0 {
+		parents := make([]string, 0, len(def.Extends))
+		for _, parent := range def.Extends {
+			parents = append(parents, prefix+parent)
+		}
+		extends = " extends " + strings.Join(parents, ", ")
+	}
 	// Interface's properties.
 	mappedProps := make([]string, 0, len(def.Properties))
 	for _, prop := range def.Properties {
-		mappedProps = append(mappedProps, generateObjectProperty(def.Key, "m.", prop))
+		mappedProps = append(mappedProps, generateObjectProperty(def.Key, prefix, prop.Key, prop))
 	}
 
-	return fmt.Sprintf(template, def.Key, strings.Join(mappedProps, "\n"))
+	return fmt.Sprintf(template, def.Key, extends, strings.Join(mappedProps, "\n"))
 }
 
 // generateDynamicQueryFilters generates a typescript interface from the given definition
 // (assumes model to be `{Prefix}DynamicQueryFilters`).
 func generateDynamicQueryFilters(defs map[string]*parser.Definition, def *parser.Definition) string {
-	for _, prop := range def.Properties {
+	// Definitions are shared with other workers; the filters' types are set on a copy.
+	filters := *def
+	filters.Properties = make([]*parser.DefinitionProperty, 0, len(def.Properties))
+	for _, defProp := range def.Properties {
+		prop := *defProp
+		filters.Properties = append(filters.Properties, &prop)
 		filter := defs[prop.Ref]
 		filterProp := filter.Properties[1]
 
@@ -50,10 +65,10 @@ func generateDynamicQueryFilters(defs map[string]*parser.Definition, def *parser
 			valueType = filterProp.Type
 		}
 		// Account for enum values.
-		if !internal.HasConstructor(valueType) {
+		if !internal.HasConstructor(filterProp) {
 			valueType = "e." + valueType
 		}
 		prop.Ref = "DynamicQueryFilter<" + valueType + ">"
 	}
-	return generateInterface(def)
+	return generateInterface(&filters, "")
 }
@@ -3,6 +3,7 @@ package typescript
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 
@@ -13,7 +14,7 @@ import (
 )
 
 // routePathParamRegex is a regexp that extracts path parameters from a route path.
-var routePathParamRegex = regexp.MustCompile(`{([a-z_]+)}`)
+var routePathParamRegex = regexp.MustCompile(`{([A-Za-z0-9_]+)}`)
 
 // generateAPIMethod generates a typescript class method from the given definition.
 func generateAPIMethod(def *parser.Path) string {
@@ -25,31 +26,68 @@ func generateAPIMethod(def *parser.Path) string {
 	// The method's name but capitalised under camel case.
 	operationAsCamel := strcase.ToCamel(def.Operation)
 
-	// The method's arguments.
-	methodArgs := ""
-	// The method's path.
-	methodPath := "'" + routePathParamRegex.ReplaceAllString(def.Key, "") + "'"
-	// The method's path parameter.
-	routePathParam := ""
-	regexResult := routePathParamRegex.FindStringSubmatch(def.Key)
-	if len(regexResult) == 2 {
-		routePathParam = internal.ShortenPathParam(regexResult[1])
-
-		methodPath += " + " + routePathParam
-		methodArgs = routePathParam + ": string"
+	// The method's arguments, starting with the path parameters in route order.
+	methodArgs := make([]string, 0, len(def.Parameters)+1)
+	for _, match := range routePathParamRegex.FindAllStringSubmatch(def.Key, -1) {
+		methodArgs = append(methodArgs, internal.PathParamAlias(def, match[1])+": "+generatePathParamType(def, match[1]))
+	}
+	// The method's path; path parameters are interpolated at their position within the route.
+	methodPath := "'" + def.Key + "'"
+	if len(methodArgs) > 0 {
+		methodPath = "`" + routePathParamRegex.ReplaceAllStringFunc(def.Key, func(s string) string {
+			return "${encodeURIComponent(" + internal.PathParamAlias(def, s[1:len(s)-1]) + ")}"
+		}) + "`"
 	}
 
+	// The method's request options, passed on to the rest client.
+	methodOptions := make([]string, 0, 4)
+	// The method's optional arguments, which must follow the required ones.
+	methodOptionalArgs := make([]string, 0, 2)
+
 	var (
 		flagPayload bool
 	)
-	// Check if a payload is required; We assume a payload if the conditions are met.
-	if len(def.Parameters) > 1 || len(def.Parameters) == 1 && def.Parameters[0].In == "body" {
+	// Check if a payload is required i.e., whether the operation has a body or form parameters.
+	if internal.IsSuitedForAPIMethod(def.Parameters) {
 		flagPayload = true
 
-		if routePathParam != "" {
-			methodArgs += ", "
+		methodArgs = append(methodArgs, "payload: d."+operationAsCamel+"Request")
+		switch internal.PayloadEncoding(def) {
+		case internal.PayloadEncodingForm:
+			methodOptions = append(methodOptions, "form: payload")
+		case internal.PayloadEncodingMultipart:
+			methodOptions = append(methodOptions, "multipart: payload")
+		default:
+			methodOptions = append(methodOptions, "payload")
 		}
-		methodArgs += "payload: d." + operationAsCamel + "Request"
+	}
+	// Query parameters are passed as a single typed object.
+	if params := internal.FilterParameters(def.Parameters, "query"); len(params) > 0 {
+		if hasRequiredParameter(params) {
+			methodArgs = append(methodArgs, "query: d."+operationAsCamel+"Query")
+		} else {
+			methodOptionalArgs = append(methodOptionalArgs, "query?: d."+operationAsCamel+"Query")
+		}
+		methodOptions = append(methodOptions, "query")
+	}
+	// Header parameters are passed as a single typed object.
+	if params := internal.FilterParameters(def.Parameters, "header"); len(params) > 0 {
+		if hasRequiredParameter(params) {
+			methodArgs = append(methodArgs, "headers: d."+operationAsCamel+"Headers")
+		} else {
+			methodOptionalArgs = append(methodOptionalArgs, "headers?: d."+operationAsCamel+"Headers")
+		}
+		methodOptions = append(methodOptions, "headers")
+	}
+	methodArgs = append(methodArgs, methodOptionalArgs...)
+	// Array parameters not serialized under the default format.
+	if formats := generateCollectionFormats(def.Parameters); formats != "" {
+		methodOptions = append(methodOptions, "collectionFormats: "+formats)
+	}
+	// Binary responses are read as such rather than as JSON.
+	responseType := generateAPIMethodResponseType(def)
+	if responseType != "json" {
+		methodOptions = append(methodOptions, "responseType: '"+responseType+"'")
 	}
 
 	// Method's REST call.
@@ -57,22 +95,129 @@ func generateAPIMethod(def *parser.Path) string {
 	if methodRestFunction == "list" {
 		methodRestFunction = "get"
 	}
+	// The method's return type, as defined by the operation's success response.
+	methodReturnType, methodReturnClass := generateAPIMethodReturnType(def, responseType)
 	// Assign the rest client method's generics.
-	methodRestFunctionGenerics := "d." + operationAsCamel + "Response"
+	methodRestFunctionGenerics := methodReturnType
 	// Assign the rest client method's arguments.
 	methodRestFunctionArgs := "path"
 	if flagPayload {
-		methodRestFunctionArgs += ", payload"
 		methodRestFunctionGenerics = fmt.Sprintf("d.%sRequest", operationAsCamel)
 	}
+	if len(methodOptions) > 0 {
+		methodRestFunctionArgs += ", { " + strings.Join(methodOptions, ", ") + " }"
+	}
+	methodRestCall := fmt.Sprintf(templates.APIClientMethodCall,
+		methodRestFunction,
+		methodRestFunctionGenerics,
+		methodRestFunctionArgs,
+	)
+
+	// The method's body.
+	methodBody := ""
+	switch {
+	case methodReturnType == "void":
+		methodBody = fmt.Sprintf(templates.APIClientMethodReturnVoid, methodRestCall)
+	case methodReturnClass:
+		methodBody = fmt.Sprintf(templates.APIClientMethodReturnClass, methodRestCall, methodReturnType)
+	default:
+		methodBody = fmt.Sprintf(templates.APIClientMethodReturnCast, methodRestCall, methodReturnType)
+	}
 
 	return fmt.Sprintf(template,
 		def.Operation,
-		methodArgs,
-		"d."+operationAsCamel+"Response",
+		strings.Join(methodArgs, ", "),
+		methodReturnType,
 		methodPath,
-		methodRestFunction,
-		methodRestFunctionGenerics,
-		methodRestFunctionArgs,
+		methodBody,
 	)
 }
+
+// generateAPIMethodReturnType returns the typescript type returned by the given operation, and
+// whether that type is a class to be constructed from the response data.
+func generateAPIMethodReturnType(def *parser.Path, responseType string) (string, bool) {
+	resp := def.SuccessResponse()
+	switch {
+	case responseType == "blob":
+		return "Blob", false
+	case responseType == "arrayBuffer":
+		return "ArrayBuffer", false
+	case resp == nil:
+		return "void", false
+	case resp.Ref != "":
+		return "d." + resp.Ref, true
+	case resp.Schema == nil:
+		return "void", false
+	default:
+		return generatePropertyType("", "d.", resp.Schema), false
+	}
+}
+
+// generatePathParamType returns the typescript type of the given path parameter; path parameters
+// are assumed to be strings unless stated otherwise.
+func generatePathParamType(def *parser.Path, key string) string {
+	for _, param := range def.Parameters {
+		if param.In == "path" && param.Key == key && param.Type != "" {
+			return generatePropertyType("", "d.", param)
+		}
+	}
+	return "string"
+}
+
+// hasRequiredParameter checks whether any of the given parameters is required.
+func hasRequiredParameter(params []*parser.DefinitionProperty) bool {
+	for _, param := range params {
+		if param.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// generateCollectionFormats generates a typescript object mapping the given array parameters to
+// their serialization format; parameters using the default format ("csv") are omitted.
+func generateCollectionFormats(params []*parser.DefinitionProperty) string {
+	formats := make([]string, 0, len(params))
+	for _, param := range params {
+		if param.Type != "array" || param.CollectionFormat == "" || param.CollectionFormat == "csv" {
+			continue
+		}
+		if param.In != "query" && param.In != "formData" {
+			continue
+		}
+		formats = append(formats, toTSPropertyKey(param.Key)+": '"+param.CollectionFormat+"'")
+	}
+	if len(formats) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(formats, ", ") + " }"
+}
+
+// generateAPIMethodResponseType returns how the rest client should read the given operation's
+// response: "json", "blob" or "arrayBuffer"; responses are binary when their schema is, or when
+// the operation only produces binary media types, whether their schema is declared or not.
+func generateAPIMethodResponseType(def *parser.Path) string {
+	resp := def.SuccessResponse()
+	// Responses without content are never binary.
+	if resp == nil || resp.Code == "204" {
+		return "json"
+	}
+	isBinary := resp.Schema != nil && internal.IsBinaryProperty(resp.Schema)
+	if !isBinary && len(def.Produces) > 0 {
+		// Responses are binary when none of their media types are textual.
+		isBinary = true
+		for _, mediaType := range def.Produces {
+			if strings.Contains(mediaType, "json") || strings.HasPrefix(mediaType, "text/") || mediaType == "*/*" {
+				isBinary = false
+			}
+		}
+	}
+	switch {
+	case !isBinary:
+		return "json"
+	case len(def.Produces) == 1 && def.Produces[0] == "application/octet-stream":
+		return "arrayBuffer"
+	default:
+		return "blob"
+	}
+}
@@ -4,19 +4,30 @@ import (
 	"sync"
 
 	"openapi-generator/internal"
+	"openapi-generator/internal/config"
 	"openapi-generator/internal/output"
 	"openapi-generator/internal/parser"
 	"openapi-generator/internal/slog"
 )
 
-// generateOutput concurrently writes the generated code to the file map.
-func generateOutput(doc *parser.Document, m output.FileMap, logger slog.Logger) {
-	enums := make(map[string]*parser.Definition)
-	reqBodies := make(map[string]*parser.Definition)
-	internal.ShakeRequestBodyDefinitions(doc.Definitions, reqBodies)
-	internal.ShakeModelDefinitions(doc.Definitions, enums)
-	internal.ShakeResponseBodyDefinitions(doc.Definitions, doc.Responses)
-	validationObjectMap := internal.FilterIntoValidationObjectMap(reqBodies, doc.Paths)
+// generateOutput concurrently writes the generated code for the given target to the file map; the
+// document, as transformed by the target's passes, is only read.
+func generateOutput(doc *parser.Document, target *config.Target, m output.FileMap, logger slog.Logger) {
+	// The document is the target's own copy, which may be reordered before being shared by the
+	// workers.
+	order := internal.Order(target.Order)
+	order.OrderDefinitions(doc.Definitions)
+	order.OrderDefinitions(doc.Responses)
+	models, enums, reqBodies := internal.SplitDefinitions(doc.Definitions)
+	// Request bodies may nest model objects, which are validated alongside them.
+	validationDefs := make(map[string]*parser.Definition, len(models)+len(reqBodies))
+	for k, v := range models {
+		validationDefs[k] = v
+	}
+	for k, v := range reqBodies {
+		validationDefs[k] = v
+	}
+	validationObjectMap := internal.FilterIntoValidationObjectMap(validationDefs, doc.Paths)
 
 	jobs := []output.Job{
 		{
@@ -27,12 +38,12 @@ func generateOutput(doc *parser.Document, m output.FileMap, logger slog.Logger)
 		{
 			OperationID: "api-client",
 			Generator:   "generateAPIClient",
-			Args:        []interface{}{doc.Paths, logger},
+			Args:        []interface{}{doc.Paths, target.Client, order, logger},
 		},
 		{
 			OperationID: "models",
 			Generator:   "generateModelTypes",
-			Args:        []interface{}{doc.Definitions, logger},
+			Args:        []interface{}{models, enums, order, logger},
 		},
 		{
 			OperationID: "enums",
@@ -42,12 +53,12 @@ func generateOutput(doc *parser.Document, m output.FileMap, logger slog.Logger)
 		{
 			OperationID: "requests",
 			Generator:   "generateRequestTypes",
-			Args:        []interface{}{doc.Paths, reqBodies, logger},
+			Args:        []interface{}{doc.Paths, reqBodies, order, logger},
 		},
 		{
 			OperationID: "validation",
 			Generator:   "generateRequestValidationObjects",
-			Args:        []interface{}{validationObjectMap, logger},
+			Args:        []interface{}{validationDefs, validationObjectMap, order, logger},
 		},
 		{
 			OperationID: "responses",
@@ -76,18 +87,22 @@ func outputWorker(
 			}
 		case "api-client":
 			paths := job.Args[0].(map[string]*parser.Path)
-			logger := job.Args[1].(slog.Logger)
+			client := job.Args[1].(config.Client)
+			order := job.Args[2].(internal.Order)
+			logger := job.Args[3].(slog.Logger)
 			file = &output.File{
 				Name: "api-client",
-				Body: generateAPIClient(paths, logger),
+				Body: generateAPIClient(paths, client, order, logger),
 			}
 		case "models":
 			defs := job.Args[0].(map[string]*parser.Definition)
-			logger := job.Args[1].(slog.Logger)
+			enums := job.Args[1].(map[string]*parser.Definition)
+			order := job.Args[2].(internal.Order)
+			logger := job.Args[3].(slog.Logger)
 			file = &output.File{
 				Name:      "models",
 				Directory: definitionsOutDir,
-				Body:      generateModelTypes(defs, logger),
+				Body:      generateModelTypes(defs, enums, order, logger),
 			}
 		case "enums":
 			defs := job.Args[0].(map[string]*parser.Definition)
@@ -100,19 +115,22 @@ func outputWorker(
 		case "requests":
 			defs := job.Args[0].(map[string]*parser.Path)
 			reqBodies := job.Args[1].(map[string]*parser.Definition)
-			logger := job.Args[2].(slog.Logger)
+			order := job.Args[2].(internal.Order)
+			logger := job.Args[3].(slog.Logger)
 			file = &output.File{
 				Name:      "requests",
 				Directory: definitionsOutDir,
-				Body:      generateRequestTypes(defs, reqBodies, logger),
+				Body:      generateRequestTypes(defs, reqBodies, order, logger),
 			}
 		case "validation":
-			defs := job.Args[0].(map[string][]*parser.DefinitionProperty)
-			logger := job.Args[1].(slog.Logger)
+			defs := job.Args[0].(map[string]*parser.Definition)
+			validations := job.Args[1].(map[string][]*parser.DefinitionProperty)
+			order := job.Args[2].(internal.Order)
+			logger := job.Args[3].(slog.Logger)
 			file = &output.File{
 				Name:      "validation",
 				Directory: definitionsOutDir,
-				Body:      generateRequestValidationObjects(defs, logger),
+				Body:      generateRequestValidationObjects(defs, validations, order, logger),
 			}
 		case "responses":
 			defs := job.Args[0].(map[string]*parser.Definition)
@@ -6,8 +6,8 @@ var APIClient = strings.TrimPrefix(`
 import { RestClient } from './rest-client';
 import * as d from './definitions';
 
-/** APIClient represents the BoardingHub API interface. */
-class APIClient {
+/** %[1]s represents the %[2]sAPI interface. */
+class %[1]s {
 	/** The HTTP client. */
 	private readonly _client: RestClient
 	/** The user's JWT'. */
@@ -18,7 +18,7 @@ class APIClient {
 		this._token = '';
 	}
 
-%s
+%[3]s
 
 	/** setToken updates the local value. */
 	setToken(value: string): void {
@@ -26,13 +26,24 @@ class APIClient {
 	}
 }
 
-/** BoardingHubAPI represents the API client instance. */
-export const BoardingHubAPI = new APIClient();
+/** %[4]s represents the API client instance. */
+export const %[4]s = new %[1]s();
 `, "\n")
 
 var APIClientMethod = strings.TrimPrefix(`
 	async %s(%s): Promise<%s> {
 		const path = %s;
-	    const respData = await this._client.%s<%s>(%s);
-		return new %[3]s(respData);
+	    %s
 	}`, "\n")
+
+var APIClientMethodCall = "this._client.%s<%s>(%s)"
+
+var APIClientMethodReturnClass = strings.TrimPrefix(`
+const respData = await %s;
+		return new %s(respData);`, "\n")
+
+var APIClientMethodReturnCast = strings.TrimPrefix(`
+const respData = await %s;
+		return respData as %s;`, "\n")
+
+var APIClientMethodReturnVoid = "await %s;"
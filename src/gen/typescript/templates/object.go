@@ -4,13 +4,17 @@ import "strings"
 
 const ObjectProperty = "\treadonly %s%s: %s;"
 
+const ObjectIndexSignature = "\treadonly [key: string]: %s;"
+
+var Map = "export type %s = Record<string, %s>;"
+
 var Interface = strings.TrimPrefix(`
-export interface %s {
+export interface %s%s {
 %s
 }`, "\n")
 
 var Class = strings.TrimPrefix(`
-export class %s {
+export class %s%s {
 %s
 
 	constructor(data: any) {
@@ -6,7 +6,7 @@ var Request = strings.Trim(`
 export interface %sRequest%s {}`, "\n")
 
 var RequestBody = strings.Trim(`
-interface %s {
+interface %s%s {
 %s
 }`, "\n")
 
@@ -16,6 +16,37 @@ enum HTTP_METHOD {
   POST = 'POST',
   PUT = 'PUT',
   DELETE = 'DELETE',
+  PATCH = 'PATCH',
+  HEAD = 'HEAD',
+  OPTIONS = 'OPTIONS',
+}
+
+/** CollectionFormat represents the serialization format of an array parameter. */
+export type CollectionFormat = 'csv' | 'ssv' | 'tsv' | 'pipes' | 'multi';
+
+const COLLECTION_FORMAT_DELIMITERS: Record<Exclude<CollectionFormat, 'multi'>, string> = {
+  csv: ',',
+  ssv: ' ',
+  tsv: '\t',
+  pipes: '|',
+};
+
+/** RequestOptions represents the options of a request. */
+export interface RequestOptions<P = any> {
+  /** The request's JSON payload. */
+  payload?: P;
+  /** The request's form payload, sent as 'application/x-www-form-urlencoded'. */
+  form?: P;
+  /** The request's multipart payload, sent as 'multipart/form-data' (e.g. file uploads). */
+  multipart?: P;
+  /** The request's query parameters. */
+  query?: object;
+  /** The array parameters' serialization formats, by parameter name (defaults to 'csv'). */
+  collectionFormats?: Record<string, CollectionFormat>;
+  /** The request's headers. */
+  headers?: object;
+  /** How the response's body is read (defaults to 'json'). */
+  responseType?: 'json' | 'blob' | 'arrayBuffer';
 }
 
 /** RestClient represents an HTTP client. */
@@ -41,60 +72,135 @@ export class RestClient {
   }
 
 	/** get executes a GET request. */
-  async get<P = any>(url: string): Promise<any> {
-    return await this.do<P>(HTTP_METHOD.GET, url, undefined, this._defaultRetryCount);
+  async get<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.GET, url, options, this._defaultRetryCount);
   }
 
 	/** post executes a POST request. */
-  async post<P = any>(url: string, payload?: P): Promise<any> {
-    return await this.do<P>(HTTP_METHOD.POST, url, payload);
+  async post<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.POST, url, options);
   }
 
 	/** put executes a PUT request. */
-  async put<P = any>(url: string, payload?: P): Promise<any> {
-    return await this.do<P>(HTTP_METHOD.PUT, url, payload);
+  async put<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.PUT, url, options);
   }
 
 	/** delete executes a DELETE request. */
-  async delete<P = any>(url: string, payload?: P): Promise<any> {
-    return await this.do<P>(HTTP_METHOD.DELETE, url, payload, this._defaultRetryCount);
+  async delete<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.DELETE, url, options, this._defaultRetryCount);
+  }
+
+	/** patch executes a PATCH request. */
+  async patch<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.PATCH, url, options);
+  }
+
+	/** head executes a HEAD request. */
+  async head<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.HEAD, url, options, this._defaultRetryCount);
+  }
+
+	/** options executes an OPTIONS request. */
+  async options<P = any>(url: string, options?: RequestOptions<P>): Promise<any> {
+    return await this.do<P>(HTTP_METHOD.OPTIONS, url, options, this._defaultRetryCount);
   }
 
 	/** do executes a request. */
   private async do<P = any>(
     method: HTTP_METHOD,
     path: string,
-    payload?: P,
+    options: RequestOptions<P> = {},
 	  retries: number = 0,
   ): Promise<any> {
     if (!this._token) throw new MissingTokenError();
     const headers: Record<string, string> = { Authorization: 'Bearer ' + this._token };
+    Object.entries(options.headers ?? {}).forEach(([key, value]) => {
+      if (value !== undefined && value !== null) headers[key] = String(value);
+    });
 
-    let body = '';
-    if (payload) {
+    let body: string | FormData | undefined;
+    if (options.payload) {
       headers['Content-Type'] = 'application/json';
-      body = JSON.stringify(payload);
+      body = JSON.stringify(options.payload);
+    } else if (options.form) {
+      headers['Content-Type'] = 'application/x-www-form-urlencoded';
+      body = encodeParams(options.form, options.collectionFormats).toString();
+    } else if (options.multipart) {
+      // The Content-Type header, including its boundary, is set by the browser.
+      body = encodeMultipart(options.multipart);
+    }
+
+    let url = API_BASE_PATH + path;
+    if (options.query) {
+      const query = encodeParams(options.query, options.collectionFormats).toString();
+      if (query) url += '?' + query;
     }
 
-    const resp = await fetch(API_BASE_PATH + path, {
+    const resp = await fetch(url, {
       method,
       headers,
       body,
     });
     if (process.env.NODE_ENV != "production")
-		console.debug(method, path, resp.status, { payload, retries });
+		console.debug(method, path, resp.status, { options, retries });
+
+		// HEAD responses have no body.
+		if (method === HTTP_METHOD.HEAD) {
+			if (resp.ok) return;
+			if (retries > 0) return this.do<P>(method, path, options, retries - 1)
+			else throw new FetchError(resp.status + ' ' + resp.statusText)
+		}
+
+		if (options.responseType && options.responseType !== 'json') {
+			if (resp.ok) return options.responseType === 'blob' ? await resp.blob() : await resp.arrayBuffer();
+			if (retries > 0) return this.do<P>(method, path, options, retries - 1)
+			else throw new FetchError(resp.status + ' ' + resp.statusText)
+		}
 
 		const respData = await resp.json();
 		if (respData.ok) return respData;
 		else {
 			const err = respData.error as APIError;
 			if (err.error_type === ErrorType.AUTHENTICATION) window.reload()
-			if (retries > 0) return this.do<P>(method, path, payload, retries - 1)
+			if (retries > 0) return this.do<P>(method, path, options, retries - 1)
 			else throw new FetchError(JSON.stringify(err, undefined, 1))
 		}
   }
 }
 
+/** encodeMultipart encodes the given payload as multipart form data. */
+function encodeMultipart(payload: object): FormData {
+  const result = new FormData();
+  Object.entries(payload).forEach(([key, value]) => {
+    if (value === undefined || value === null) return;
+    (Array.isArray(value) ? value : [value]).forEach((v) => {
+      if (v instanceof Blob) result.append(key, v);
+      else result.append(key, typeof v === 'object' ? JSON.stringify(v) : String(v));
+    });
+  });
+  return result;
+}
+
+/** encodeParams encodes the given parameters, serializing arrays as per their collection format. */
+function encodeParams(
+  params: object,
+  collectionFormats: Record<string, CollectionFormat> = {},
+): URLSearchParams {
+  const result = new URLSearchParams();
+  Object.entries(params).forEach(([key, value]) => {
+    if (value === undefined || value === null) return;
+    if (!Array.isArray(value)) {
+      result.append(key, String(value));
+      return;
+    }
+    const format = collectionFormats[key] ?? 'csv';
+    if (format === 'multi') value.forEach((v) => result.append(key, String(v)));
+    else result.append(key, value.map(String).join(COLLECTION_FORMAT_DELIMITERS[format]));
+  });
+  return result;
+}
+
 /**
  * RuntimeEnvironmentError represents an error that occurred because the runtime environment is not
  * a browser.
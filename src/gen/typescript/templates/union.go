@@ -0,0 +1,25 @@
+package templates
+
+import "strings"
+
+var Union = "export type %s = %s;"
+
+var UnionNarrowing = strings.TrimPrefix(`
+/** is%[1]s%[2]s checks whether the given %[1]s is a %[2]s. */
+export function is%[1]s%[2]s(value: %[1]s): value is %[2]s {
+	return value%[3]s === %[4]s;
+}`, "\n")
+
+var UnionFactory = strings.TrimPrefix(`
+/** new%[1]s returns a new instance of the %[1]s variant matching the given data's discriminator. */
+export function new%[1]s(data: any): %[1]s {
+	switch (data%[2]s) {
+%[3]s
+		default:
+			%[4]s
+	}
+}`, "\n")
+
+var UnionFactoryCase = strings.TrimPrefix(`
+		case %s:
+			return new %s(data);`, "\n")
@@ -1,6 +1,9 @@
 package typescript
 
 import (
+	"openapi-generator/gen"
+	"openapi-generator/internal"
+	"openapi-generator/internal/config"
 	"openapi-generator/internal/output"
 	"openapi-generator/internal/parser"
 
@@ -10,12 +13,46 @@ import (
 
 const definitionsOutDir = "definitions/"
 
+func init() {
+	gen.Register("typescript", NewGenerator)
+}
+
+// generator represents the typescript implementation of `gen.Generator`.
+type generator struct {
+	target *config.Target
+	logger slog.Logger
+}
+
+// NewGenerator returns a new typescript implementation of `gen.Generator` for the given target.
+func NewGenerator(target *config.Target, logger slog.Logger) gen.Generator {
+	return &generator{
+		target: target,
+		logger: logger,
+	}
+}
+
+func (g *generator) Name() string {
+	return "typescript"
+}
+
+func (g *generator) Extension() string {
+	return ".ts"
+}
+
+func (g *generator) CommentStyle() output.CommentStyle {
+	return output.CommentStyleJSDoc
+}
+
+func (g *generator) OverrideExtensions() (name, typ string) {
+	return internal.ExtensionTSName, internal.ExtensionTSType
+}
+
 // Generate generates the typescript files for the given spec.
-func Generate(doc *parser.Document, logger slog.Logger) []*output.File {
-	logger.SetPrefix("[typescript.Generate] ")
+func (g *generator) Generate(doc *parser.Document) ([]*output.File, error) {
+	g.logger.SetPrefix("[typescript.Generate] ")
 
 	fm := output.NewFileMap()
-	generateOutput(doc, fm, logger)
+	generateOutput(doc, g.target, fm, g.logger)
 
 	// ../packages/
 	// ├── definitions
@@ -50,5 +87,5 @@ func Generate(doc *parser.Document, logger slog.Logger) []*output.File {
 		files = append(files, fm.Get(k))
 	}
 
-	return files
+	return files, nil
 }
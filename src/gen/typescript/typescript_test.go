@@ -0,0 +1,314 @@
+package typescript
+
+import (
+	"strings"
+	"testing"
+
+	"openapi-generator/gen"
+	"openapi-generator/internal/config"
+	"openapi-generator/internal/parser"
+	"openapi-generator/internal/slog"
+)
+
+// generateFiles returns the bodies of the files generated from the given specification, by path
+// e.g., "definitions/models.ts", as per the given overrides, if any.
+func generateFiles(t *testing.T, spec string, overrides config.Overrides) map[string]string {
+	t.Helper()
+	doc, err := parser.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := &config.Target{
+		Name:      "typescript",
+		Overrides: overrides,
+		Order:     "sorted",
+		Client:    config.Client{Class: "APIClient", Instance: "API"},
+	}
+	if doc, err = gen.Transform(doc, target); err != nil {
+		t.Fatal(err)
+	}
+	files, err := NewGenerator(target, slog.NewLogger("")).Generate(doc)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	result := make(map[string]string, len(files))
+	for _, f := range files {
+		result[f.Directory+f.Name+".ts"] = f.Body
+	}
+	return result
+}
+
+// assertContains checks that the given generated file holds each of the given snippets.
+func assertContains(t *testing.T, files map[string]string, name string, snippets ...string) {
+	t.Helper()
+	body, ok := files[name]
+	if !ok {
+		t.Fatalf("'%s' isn't generated", name)
+	}
+	for _, s := range snippets {
+		if !strings.Contains(body, s) {
+			t.Errorf("'%s' lacks %q:\n%s", name, s, body)
+		}
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	files := generateFiles(t, `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /organisations/{organisation_id}/members/{member_id}:
+    get:
+      operationId: getOrgMember
+      parameters:
+        - {in: path, name: member_id, type: integer, required: true}
+        - {in: path, name: organisation_id, type: string, required: true}
+      responses:
+        '204': {description: ok}
+`, config.Overrides{})
+	// Path parameters are taken in route order, whatever their declaration order.
+	assertContains(t, files, "api-client.ts",
+		"async getOrgMember(organisation_id: string, member_id: number): Promise<void> {",
+		"const path = `/organisations/${encodeURIComponent(organisation_id)}/members/${encodeURIComponent(member_id)}`;",
+	)
+}
+
+func TestQueryHeaderAndFormParameters(t *testing.T) {
+	files := generateFiles(t, `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members:
+    get:
+      operationId: listMembers
+      parameters:
+        - {name: limit, in: query, type: integer}
+        - {name: tags, in: query, type: array, items: {type: string}, collectionFormat: multi}
+        - {name: X-Request-ID, in: header, type: string, required: true}
+      responses:
+        '204': {description: ok}
+  /login:
+    post:
+      operationId: login
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - {name: username, in: formData, type: string, required: true}
+      responses:
+        '204': {description: ok}
+`, config.Overrides{})
+	// Optional arguments follow the required ones.
+	assertContains(t, files, "api-client.ts",
+		"async listMembers(headers: d.ListMembersHeaders, query?: d.ListMembersQuery): Promise<void> {",
+		"await this._client.get<void>(path, { query, headers, collectionFormats: { tags: 'multi' } });",
+		"await this._client.post<d.LoginRequest>(path, { form: payload });",
+	)
+	assertContains(t, files, "definitions/requests.ts",
+		"export interface ListMembersQuery {",
+		"readonly limit?: number;",
+		"readonly tags?: string[];",
+		"export interface ListMembersHeaders {",
+		"readonly 'X-Request-ID': string;",
+		"export interface LoginRequest {",
+		"readonly username: string;",
+	)
+}
+
+func TestFileUploadsAndDownloads(t *testing.T) {
+	files := generateFiles(t, `
+swagger: "2.0"
+info: {title: t, version: "1"}
+produces: [application/json]
+paths:
+  /members/{member_id}/avatar:
+    post:
+      operationId: uploadAvatar
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: member_id, in: path, required: true, type: string}
+        - {name: file, in: formData, type: file, required: true}
+      responses:
+        '204': {description: ok}
+    get:
+      operationId: getAvatar
+      produces: [image/png]
+      parameters:
+        - {name: member_id, in: path, required: true, type: string}
+      responses:
+        '200': {description: ok, schema: {type: file}}
+  /export:
+    get:
+      operationId: exportData
+      produces: [application/octet-stream]
+      responses:
+        '200': {description: ok, schema: {type: string, format: binary}}
+`, config.Overrides{})
+	assertContains(t, files, "api-client.ts",
+		"await this._client.post<d.UploadAvatarRequest>(path, { multipart: payload });",
+		"async getAvatar(member_id: string): Promise<Blob> {",
+		"this._client.get<Blob>(path, { responseType: 'blob' });",
+		"async exportData(): Promise<ArrayBuffer> {",
+		"this._client.get<ArrayBuffer>(path, { responseType: 'arrayBuffer' });",
+	)
+	assertContains(t, files, "definitions/requests.ts", "readonly file: File | Blob;")
+}
+
+func TestCompositions(t *testing.T) {
+	files := generateFiles(t, `
+openapi: 3.0.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Entity:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+    ViewEntity:
+      oneOf:
+        - $ref: '#/components/schemas/GroupView'
+        - $ref: '#/components/schemas/MemberView'
+      discriminator:
+        propertyName: entity_type
+        mapping:
+          group: '#/components/schemas/GroupView'
+    GroupView:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            entity_type: {type: string}
+            name: {type: string}
+    MemberView:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            entity_type: {type: string}
+    Anything:
+      anyOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: string
+`, config.Overrides{})
+	assertContains(t, files, "definitions/models.ts",
+		"export class GroupView extends Entity {",
+		"export type ViewEntity = GroupView | MemberView;",
+		"export type Anything = Entity | string;",
+		"export function isViewEntityGroupView(value: ViewEntity): value is GroupView {\n\treturn value.entity_type === 'group';",
+		// Variants without a mapping are discriminated by name.
+		"case 'MemberView':\n\t\t\treturn new MemberView(data);",
+	)
+}
+
+func TestMaps(t *testing.T) {
+	files := generateFiles(t, `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Cat:
+      type: object
+      properties: {name: {type: string}}
+    Scores:
+      type: object
+      additionalProperties: {type: integer}
+    Cats:
+      type: object
+      additionalProperties: {$ref: '#/components/schemas/Cat'}
+    Anything:
+      type: object
+      additionalProperties: true
+    Holder:
+      type: object
+      required: [cats]
+      properties:
+        cats: {$ref: '#/components/schemas/Cats'}
+        scores: {$ref: '#/components/schemas/Scores'}
+`, config.Overrides{})
+	assertContains(t, files, "definitions/models.ts",
+		"export type Scores = Record<string, number>;",
+		"export type Cats = Record<string, Cat>;",
+		"export type Anything = Record<string, unknown>;",
+		// Maps of models construct each of their values.
+		"this.cats = Object.fromEntries(Object.entries(data.cats).map(([k, v]: [string, any]) => [k, new Cat(v)]));",
+		"this.scores = data.scores;",
+	)
+}
+
+func TestHTTPMethods(t *testing.T) {
+	files := generateFiles(t, `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members/{member_id}:
+    parameters:
+      - {in: path, name: member_id, type: string, required: true}
+    patch:
+      operationId: patchMember
+      responses:
+        '204': {description: ok}
+    head:
+      operationId: headMember
+      responses:
+        '204': {description: ok}
+    options:
+      operationId: memberOptions
+      responses:
+        '204': {description: ok}
+`, config.Overrides{})
+	// Path-level parameters are taken by each of the path's operations.
+	assertContains(t, files, "api-client.ts",
+		"async patchMember(member_id: string): Promise<void> {",
+		"await this._client.patch<void>(path);",
+		"await this._client.head<void>(path);",
+		"await this._client.options<void>(path);",
+	)
+	assertContains(t, files, "rest-client.ts",
+		"PATCH = 'PATCH',",
+		"async patch<P = any>(",
+		"async head<P = any>(",
+		"async options<P = any>(",
+	)
+}
+
+func TestVendorExtensions(t *testing.T) {
+	files := generateFiles(t, `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Cat:
+      type: object
+      x-ts-name: Pet
+      properties:
+        name: {type: string}
+    Member:
+      type: object
+      properties:
+        2fa: {$ref: '#/components/schemas/Cat'}
+        alias_me: {type: string, x-ts-name: aliased}
+        when: {type: string, format: date-time, x-ts-type: ExtendedDate}
+        plain: {type: string, x-ts-type: 'string | null'}
+        role: {type: string, enum: [admin, user], x-enum-name: MemberRole}
+        former_role: {type: string, enum: [admin, user], x-enum-name: MemberRole}
+        country_code: {type: string}
+`, config.Overrides{
+		Names: map[string]string{"Member.country_code": "country"},
+		Types: map[string]string{"Member.country_code": "Country"},
+	})
+	assertContains(t, files, "definitions/models.ts",
+		"export class Pet {",
+		// Properties are aliased, but read from the data under their key.
+		"this['2fa'] = new Pet(data['2fa']);",
+		"this.aliased = data.alias_me;",
+		// Overridden types are constructed when they're classes, and cast otherwise.
+		"this.when = new ExtendedDate(data.when);",
+		"this.plain = data.plain as string | null;",
+		"this.country = new Country(data.country_code);",
+		"readonly role?: e.MemberRole;",
+		"readonly former_role?: e.MemberRole;",
+	)
+	assertContains(t, files, "definitions/enums.ts", "export enum MemberRole {")
+}
@@ -0,0 +1,119 @@
+package typescript
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openapi-generator/internal/parser"
+
+	"openapi-generator/gen/typescript/templates"
+)
+
+// generateUnion generates a typescript union type from the given oneOf/anyOf definition; the given
+// enumerations are imported from './enums'.
+func generateUnion(defs map[string]*parser.Definition, def *parser.Definition, enums map[string]*parser.Definition) string {
+	template := templates.Union
+
+	// Union description.
+	if defDesc := def.Description; defDesc != "" {
+		template = toJSDoc("", defDesc) + template
+	}
+	variants := make([]string, 0, len(def.OneOf)+len(def.AnyOf))
+	for _, variant := range append(append([]string{}, def.OneOf...), def.AnyOf...) {
+		variants = append(variants, toTSVariant(variant, enums))
+	}
+	if len(variants) == 0 {
+		variants = []string{"never"}
+	}
+	result := fmt.Sprintf(template, def.Key, strings.Join(variants, " | "))
+
+	if helpers := generateDiscriminatorHelpers(defs, def); helpers != "" {
+		result += "\n\n" + helpers
+	}
+	return result
+}
+
+// generateDiscriminatorHelpers generates the narrowing helpers and the factory of the given
+// discriminated definition; the factory falls back to the definition itself when it isn't a union.
+// Helpers narrow instances, whose discriminating property may be aliased, whereas the factory reads
+// data as sent over the wire.
+func generateDiscriminatorHelpers(defs map[string]*parser.Definition, def *parser.Definition) string {
+	discriminator := def.Discriminator
+	if discriminator == nil || len(discriminator.Mapping) == 0 {
+		return ""
+	}
+	accessor := toTSPropertyAccessor(discriminator.PropertyName)
+
+	values := make([]string, 0, len(discriminator.Mapping))
+	for value := range discriminator.Mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	instanceAccessor := toTSPropertyAccessor(discriminatorName(defs, def, values))
+
+	// Narrowing helpers, one per variant.
+	helpers := make([]string, 0, len(values)+1)
+	// Factory's cases, one per discriminating value.
+	cases := make([]string, 0, len(values))
+	for _, value := range values {
+		variant := discriminator.Mapping[value]
+		helpers = append(helpers, fmt.Sprintf(templates.UnionNarrowing, def.Key, variant, instanceAccessor, toTSLiteral(value)))
+		cases = append(cases, fmt.Sprintf(templates.UnionFactoryCase, toTSLiteral(value), variant))
+	}
+
+	// The global Error is referenced explicitly, as models may declare an `Error` of their own.
+	fallback := fmt.Sprintf("throw new globalThis.Error('new%s: unknown %s ' + data%s);", def.Key, discriminator.PropertyName, accessor)
+	if def.Type != "union" {
+		fallback = fmt.Sprintf("return new %s(data);", def.Key)
+	}
+	helpers = append(helpers, fmt.Sprintf(templates.UnionFactory, def.Key, accessor, strings.Join(cases, "\n"), fallback))
+
+	return strings.Join(helpers, "\n\n")
+}
+
+// discriminatorName returns the name of the given definition's discriminating property within
+// classes, as declared by the definition itself or else by its variants, in order of the given
+// discriminating values.
+func discriminatorName(defs map[string]*parser.Definition, def *parser.Definition, values []string) string {
+	keys := []string{def.Key}
+	for _, value := range values {
+		keys = append(keys, def.Discriminator.Mapping[value])
+	}
+	for _, key := range keys {
+		d, ok := defs[key]
+		if !ok {
+			continue
+		}
+		for _, prop := range d.Properties {
+			if prop.Key == def.Discriminator.PropertyName {
+				return classPropertyName(prop)
+			}
+		}
+	}
+	return def.Discriminator.PropertyName
+}
+
+// toTSVariant returns the typescript type of the given union variant, be it a definition's key or a
+// type e.g., "integer" or "Pet[]"; the given enumerations are imported from './enums'.
+func toTSVariant(variant string, enums map[string]*parser.Definition) string {
+	if elem := strings.TrimSuffix(variant, "[]"); elem != variant {
+		return toTSVariant(elem, enums) + "[]"
+	}
+	if _, ok := enums[variant]; ok {
+		return "e." + variant
+	}
+	switch variant {
+	case "array":
+		return "unknown[]"
+	case "string", "integer", "number", "boolean", "object", "null":
+		return toTSType(variant)
+	default:
+		return variant
+	}
+}
+
+// isPolymorphic checks whether the given definition is a union or a discriminated definition.
+func isPolymorphic(def *parser.Definition) bool {
+	return def != nil && (def.Type == "union" || def.Discriminator != nil && len(def.Discriminator.Mapping) > 0)
+}
@@ -1,12 +1,80 @@
 package typescript
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
 
 // toJSDoc returns the given description as a JSDoc comment.
 func toJSDoc(indent, desc string) string {
 	return indent + "/** " + desc + " */\n"
 }
 
+// toJSDocWithExamples returns the given description and examples as a JSDoc comment; it falls back
+// to `toJSDoc` when there are no examples.
+func toJSDocWithExamples(indent, desc string, examples []interface{}) string {
+	if len(examples) == 0 {
+		return toJSDoc(indent, desc)
+	}
+	lines := []string{indent + "/**"}
+	if desc != "" {
+		lines = append(lines, indent+" * "+desc, indent+" *")
+	}
+	for _, example := range examples {
+		lines = append(lines, indent+" * @example "+toTSLiteral(example))
+	}
+	return strings.Join(append(lines, indent+" */"), "\n") + "\n"
+}
+
+// tsIdentifierRegex matches valid typescript identifiers.
+var tsIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// toTSPropertyKey returns the given key as a typescript property key, quoting it when it isn't
+// a valid identifier (e.g. "X-Request-ID").
+func toTSPropertyKey(key string) string {
+	if tsIdentifierRegex.MatchString(key) {
+		return key
+	}
+	return toTSLiteral(key)
+}
+
+// toTSPropertyAccessor returns the typescript accessor of the given property key e.g., ".key" or
+// "['X-Key']".
+func toTSPropertyAccessor(key string) string {
+	if tsIdentifierRegex.MatchString(key) {
+		return "." + key
+	}
+	return "[" + toTSLiteral(key) + "]"
+}
+
+// toTSType returns the typescript type matching the given schema type.
+func toTSType(t string) string {
+	switch t {
+	case "integer":
+		return "number"
+	case "null":
+		return "null"
+	case "":
+		return "unknown"
+	default:
+		return t
+	}
+}
+
+// toTSLiteral returns the given value as a typescript literal.
+func toTSLiteral(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
 // appendValidationMessageToMethodCall appends the given message to the given call and returns
 // the formatted result.
 func appendValidationMessageToMethodCall(call, msg string, args ...interface{}) string {
@@ -13,11 +13,13 @@ import (
 )
 
 // generateRequestClassValidationObject generates a request class' validation object from the
-// given definition.
-func generateRequestClassValidationObject(key string, props []*parser.DefinitionProperty) string {
+// given definition, whose properties are listed as per the given order.
+func generateRequestClassValidationObject(
+	defs map[string]*parser.Definition, key string, props []*parser.DefinitionProperty, order internal.Order,
+) string {
 	mappedObjects := make([]string, 0, len(props))
-	for _, prop := range internal.SortProperties(props) {
-		mappedObjects = append(mappedObjects, generateRequestValidationProperty("\t", prop))
+	for _, prop := range order.Properties(props) {
+		mappedObjects = append(mappedObjects, generateRequestValidationProperty(defs, map[string]bool{}, "\t", prop, order))
 	}
 
 	return fmt.Sprintf(templates.RequestValidation,
@@ -27,15 +29,38 @@ func generateRequestClassValidationObject(key string, props []*parser.Definition
 }
 
 // generateRequestValidationProperty generates a validation object property from the given
-// definition.
-func generateRequestValidationProperty(initialIndent string, prop *parser.DefinitionProperty) string {
-	// TODO(MZ): nested objects like Address.
+// definition; nested objects are looked up from the given definitions, unless already being
+// visited, and their properties are listed as per the given order.
+func generateRequestValidationProperty(
+	defs map[string]*parser.Definition, visiting map[string]bool, initialIndent string, prop *parser.DefinitionProperty,
+	order internal.Order,
+) string {
 	// TODO(MZ): dependency-bound validation such as HostMemberRelationship.
 	// https://stackoverflow.com/questions/61962784/yup-nested-schema-validation
 
 	result := initialIndent + strcase.ToLowerCamel(prop.Key)
 	indent := "\n\t" + initialIndent
 
+	// Nested objects are validated against their own properties.
+	if internal.IsNestedObject(defs, prop) && !visiting[prop.Ref] {
+		visiting[prop.Ref] = true
+		defer delete(visiting, prop.Ref)
+
+		mappedProps := make([]string, 0, len(defs[prop.Ref].Properties))
+		for _, nestedProp := range order.Properties(defs[prop.Ref].Properties) {
+			if internal.IsPropSuitableForValidation(nestedProp.Type) || internal.IsNestedObject(defs, nestedProp) {
+				mappedProps = append(mappedProps, generateRequestValidationProperty(defs, visiting, initialIndent+"\t", nestedProp, order))
+			}
+		}
+		result += ": yupObject({\n" + strings.Join(mappedProps, "\n") + "\n" + initialIndent + "})"
+		if required := prop.Required; required {
+			result += indent + fmt.Sprintf(".required('%s')", internal.ValidationMessageRequired)
+		} else {
+			result += indent + ".default(undefined)"
+		}
+		return result + ","
+	}
+
 	switch prop.Type {
 	case "string":
 		result += ": yupString()"
@@ -72,13 +97,13 @@ func generateRequestValidationProperty(initialIndent string, prop *parser.Defini
 			result += indent + fmt.Sprintf(".required('%s')", internal.ValidationMessageRequired)
 		}
 		if min := prop.Validation.Min; min != 0 {
-			result += indent + appendValidationMessageToMethodCall(".min(%[1]d",
+			result += indent + appendValidationMessageToMethodCall(".min(%[1]v",
 				internal.ValidationMessageMin,
 				min,
 			)
 		}
 		if max := prop.Validation.Max; max != 0 {
-			result += indent + appendValidationMessageToMethodCall(".max(%[1]d",
+			result += indent + appendValidationMessageToMethodCall(".max(%[1]v",
 				internal.ValidationMessageMax,
 				max,
 			)
@@ -3,6 +3,7 @@ module openapi-generator
 go 1.17
 
 require (
-	github.com/iancoleman/strcase v0.2.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
+	github.com/iancoleman/strcase v0.2.0
+	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
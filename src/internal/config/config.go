@@ -0,0 +1,298 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"openapi-generator/internal/output"
+	"openapi-generator/internal/transform"
+)
+
+// DefaultFileName is the name of the configuration file looked up when none is specified.
+const DefaultFileName = "openapi-parser.yaml"
+
+// DefaultWatermark is the watermark text used when none is configured.
+const DefaultWatermark = "This file was generated by the OpenAPI Code Generator. Do not edit directly."
+
+// Config represents the generator's configuration.
+type Config struct {
+	// The specification files to generate code from, by name; relative paths are relative to the
+	// configuration file.
+	Inputs map[string]string `yaml:"inputs"`
+	// The targets to generate code for.
+	Targets []*Target `yaml:"targets"`
+	// The text of the watermark prepended to each generated file.
+	Watermark string `yaml:"watermark"`
+	// Whether the watermark holds the generation's timestamp, at the cost of every file changing on
+	// every generation; it otherwise holds a hash of the specification and generator's version.
+	Timestamp bool `yaml:"timestamp"`
+}
+
+// Target represents the code to generate for a given language.
+type Target struct {
+	// The target's language e.g., "typescript".
+	Name string `yaml:"name"`
+	// The name of the input to generate code from; defaults to the sole input, if any.
+	Input string `yaml:"input"`
+	// The directory to write the generated files to; relative paths are relative to the
+	// configuration file.
+	Output string `yaml:"output"`
+	// The generated API client.
+	Client Client `yaml:"client"`
+	// The overrides applied to the specification.
+	Overrides Overrides `yaml:"overrides"`
+	// The order of the generated properties and operations: "sorted" (default) or "spec" i.e., as
+	// declared by the specification.
+	Order string `yaml:"order"`
+	// The transformation passes enabled, or disabled, by name e.g., "rename"; the others run if
+	// they do by default (see `transform.Passes`).
+	Passes map[string]bool `yaml:"passes"`
+	// The executable generating the target's code, for targets implemented out of process; paths
+	// holding a separator are relative to the configuration file, others are looked up in $PATH.
+	Plugin string `yaml:"plugin"`
+	// The extension of the files generated by the plugin e.g., ".py".
+	Extension string `yaml:"extension"`
+	// The style of the comments of the files generated by the plugin, such as the watermark:
+	// "jsdoc", "slashes", "hash" or "none"; defaults to the one conventional for the extension,
+	// and is required for other extensions.
+	Comment string `yaml:"comment"`
+	// The options passed on to the plugin.
+	Options map[string]interface{} `yaml:"options"`
+}
+
+// Client represents the naming of a generated API client.
+type Client struct {
+	// The API's product name, as used within documentation e.g., "Acme".
+	Product string `yaml:"product"`
+	// The client's class name.
+	Class string `yaml:"class"`
+	// The client's exported instance name.
+	Instance string `yaml:"instance"`
+}
+
+// Overrides represents the overrides applied to a specification before generating code from it;
+// definitions are keyed by name e.g., "Role", and their properties by "{Definition}.{property}"
+// e.g., "Member.created_at".
+type Overrides struct {
+	// The names given to definitions and properties within generated code.
+	Names map[string]string `yaml:"names"`
+	// The types given to properties within generated code.
+	Types map[string]string `yaml:"types"`
+}
+
+// Flags represents the command-line flags overriding a configuration.
+type Flags struct {
+	// The input to generate code from, in place of the configured ones; "-" stands for the
+	// standard input.
+	Input string
+	// The directory to write the generated files to; only a single target may be selected.
+	Output string
+	// The target to generate code for; configured targets are filtered by name.
+	Target string
+}
+
+// Load reads the configuration file at the given path, if any, and applies the given flags; paths
+// given through flags are relative to the working directory.
+func Load(path string, flags Flags) (*Config, error) {
+	cfg := &Config{}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if err = yaml.UnmarshalStrict(b, cfg); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+		cfg.resolvePaths(filepath.Dir(path))
+	}
+	if err := cfg.apply(flags, path == ""); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := cfg.init(); err != nil {
+		if path != "" {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return cfg, nil
+}
+
+// resolvePaths resolves the configuration's relative paths against the given directory.
+func (c *Config) resolvePaths(dir string) {
+	for name, input := range c.Inputs {
+		c.Inputs[name] = resolvePath(dir, input)
+	}
+	for _, t := range c.Targets {
+		if t != nil && t.Output != "" {
+			t.Output = resolvePath(dir, t.Output)
+		}
+		if t != nil && strings.ContainsRune(t.Plugin, filepath.Separator) {
+			// Made absolute, so as not to be looked up in $PATH e.g., "./plugin".
+			if plugin, err := filepath.Abs(resolvePath(dir, t.Plugin)); err == nil {
+				t.Plugin = plugin
+			}
+		}
+	}
+}
+
+// apply applies the given flags to the configuration; unknown targets are only added when there is
+// no configuration file.
+func (c *Config) apply(flags Flags, noFile bool) error {
+	if flags.Input != "" {
+		c.Inputs = map[string]string{"input": flags.Input}
+		for _, t := range c.Targets {
+			if t != nil {
+				t.Input = ""
+			}
+		}
+	}
+	if flags.Target != "" {
+		targets := make([]*Target, 0, 1)
+		for _, t := range c.Targets {
+			if t != nil && t.Name == flags.Target {
+				targets = append(targets, t)
+			}
+		}
+		switch {
+		case len(targets) > 0:
+		case noFile:
+			targets = append(targets, &Target{Name: flags.Target})
+		default:
+			return fmt.Errorf("unknown target '%s'", flags.Target)
+		}
+		c.Targets = targets
+	}
+	if flags.Output != "" {
+		if len(c.Targets) != 1 {
+			return fmt.Errorf("an output directory may only be given for a single target")
+		}
+		c.Targets[0].Output = flags.Output
+	}
+	return nil
+}
+
+// init validates the configuration, and sets its defaults.
+func (c *Config) init() error {
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("at least one input must be declared")
+	}
+	if c.Watermark == "" {
+		c.Watermark = DefaultWatermark
+	}
+	for i, t := range c.Targets {
+		if t == nil || t.Name == "" {
+			return fmt.Errorf("targets[%d]: a name must be specified", i)
+		}
+		if t.Output == "" {
+			return fmt.Errorf("targets[%d]: an output directory must be specified", i)
+		}
+		switch {
+		case t.Input != "":
+			if _, ok := c.Inputs[t.Input]; !ok {
+				return fmt.Errorf("targets[%d]: unknown input '%s'", i, t.Input)
+			}
+		case len(c.Inputs) == 1:
+			for name := range c.Inputs {
+				t.Input = name
+			}
+		default:
+			return fmt.Errorf("targets[%d]: an input must be specified, one of %v", i, c.InputNames())
+		}
+		for _, name := range sortedKeys(t.Passes) {
+			if !transform.IsPass(name) {
+				return fmt.Errorf("targets[%d]: unknown pass '%s', expected one of %v", i, name, transform.Names())
+			}
+		}
+		switch t.Order {
+		case "":
+			t.Order = "sorted"
+		case "sorted", "spec":
+		default:
+			return fmt.Errorf("targets[%d]: unknown order '%s', expected \"sorted\" or \"spec\"", i, t.Order)
+		}
+		if t.Comment == "" && t.Plugin != "" {
+			name, ok := output.CommentStyleNameByExtension(t.Extension)
+			if !ok {
+				return fmt.Errorf("targets[%d]: a comment style must be specified for extension '%s'", i, t.Extension)
+			}
+			t.Comment = name
+		}
+		if t.Comment == "" {
+			t.Comment = "none"
+		}
+		if _, ok := output.CommentStyleByName(t.Comment); !ok {
+			return fmt.Errorf("targets[%d]: unknown comment style '%s'", i, t.Comment)
+		}
+		if t.Plugin != "" {
+			t.Options, _ = normalizeValue(t.Options).(map[string]interface{})
+		}
+		if t.Client.Class == "" {
+			t.Client.Class = "APIClient"
+		}
+		if t.Client.Instance == "" {
+			t.Client.Instance = t.Client.Product + "API"
+		}
+	}
+	return nil
+}
+
+// InputNames returns the names of the configuration's inputs, sorted alphabetically.
+func (c *Config) InputNames() []string {
+	names := make([]string, 0, len(c.Inputs))
+	for name := range c.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedKeys returns the keys of the given map, sorted alphabetically.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeValue converts the given YAML value into a value which can be encoded as JSON i.e.,
+// nested maps are converted into `map[string]interface{}`.
+func normalizeValue(v interface{}) interface{} {
+	switch vTyped := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(vTyped))
+		for k, val := range vTyped {
+			result[k] = normalizeValue(val)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(vTyped))
+		for k, val := range vTyped {
+			result[fmt.Sprint(k)] = normalizeValue(val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(vTyped))
+		for _, val := range vTyped {
+			result = append(result, normalizeValue(val))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// resolvePath resolves the given path against the given directory, unless it's absolute or
+// designates the standard input.
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) || path == "-" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
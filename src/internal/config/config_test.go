@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		flags  Flags
+		// check checks the loaded configuration; nil if it's invalid, in which case the error is
+		// expected to hold wantErr.
+		check   func(t *testing.T, dir string, cfg *Config)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			config: `
+inputs: {api: ../openapi/api.yaml}
+targets:
+  - name: typescript
+    output: ../web-sdk/src/api
+    client: {product: Acme}
+`,
+			check: func(t *testing.T, dir string, cfg *Config) {
+				target := cfg.Targets[0]
+				// Relative paths are relative to the configuration file.
+				if want := filepath.Join(dir, "../openapi/api.yaml"); cfg.Inputs["api"] != want {
+					t.Errorf("input = %q, want %q", cfg.Inputs["api"], want)
+				}
+				if want := filepath.Join(dir, "../web-sdk/src/api"); target.Output != want {
+					t.Errorf("output = %q, want %q", target.Output, want)
+				}
+				if target.Input != "api" || target.Order != "sorted" || target.Comment != "none" {
+					t.Errorf("input, order, comment = %q, %q, %q; want api, sorted, none", target.Input, target.Order, target.Comment)
+				}
+				if target.Client.Class != "APIClient" || target.Client.Instance != "AcmeAPI" {
+					t.Errorf("client = %+v, want APIClient and AcmeAPI", target.Client)
+				}
+				if cfg.Watermark != DefaultWatermark {
+					t.Errorf("watermark = %q, want the default one", cfg.Watermark)
+				}
+			},
+		},
+		{
+			name: "flags",
+			config: `
+inputs: {api: api.yaml}
+targets:
+  - {name: typescript, output: web}
+  - {name: python, output: py, plugin: gen-py, extension: .py}
+`,
+			flags: Flags{Input: "-", Target: "python", Output: "out"},
+			check: func(t *testing.T, dir string, cfg *Config) {
+				if len(cfg.Targets) != 1 || cfg.Targets[0].Name != "python" {
+					t.Fatalf("targets = %d, want python only", len(cfg.Targets))
+				}
+				// Flags' paths are relative to the working directory.
+				target := cfg.Targets[0]
+				if cfg.Inputs[target.Input] != "-" || target.Output != "out" {
+					t.Errorf("input, output = %q, %q; want -, out", cfg.Inputs[target.Input], target.Output)
+				}
+				if target.Comment != "hash" {
+					t.Errorf("comment = %q, want the extension's i.e., hash", target.Comment)
+				}
+			},
+		},
+		{
+			name:    "unknown key",
+			config:  "inputs: {api: api.yaml}\ntargets: [{name: typescript, output: web, ouptut: web}]\n",
+			wantErr: "ouptut",
+		},
+		{
+			name:    "unknown input",
+			config:  "inputs: {api: api.yaml}\ntargets: [{name: typescript, output: web, input: spec}]\n",
+			wantErr: "unknown input 'spec'",
+		},
+		{
+			name:    "unknown order",
+			config:  "inputs: {api: api.yaml}\ntargets: [{name: typescript, output: web, order: random}]\n",
+			wantErr: "unknown order 'random'",
+		},
+		{
+			name:    "unknown pass",
+			config:  "inputs: {api: api.yaml}\ntargets: [{name: typescript, output: web, passes: {inline: true}}]\n",
+			wantErr: "unknown pass 'inline'",
+		},
+		{
+			name:    "unknown plugin comment style",
+			config:  "inputs: {api: api.yaml}\ntargets: [{name: x, output: out, plugin: gen-x, extension: .x}]\n",
+			wantErr: "a comment style must be specified for extension '.x'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "openapi-parser.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path, tt.flags)
+			if tt.check == nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want it to hold %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			tt.check(t, dir, cfg)
+		})
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	cfg, err := Load("", Flags{Input: "api.yaml", Target: "typescript", Output: "out"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Targets) != 1 || cfg.Targets[0].Name != "typescript" || cfg.Targets[0].Output != "out" {
+		t.Errorf("targets = %+v, want typescript written to out", cfg.Targets)
+	}
+	if _, err = Load("", Flags{Input: "api.yaml"}); err != nil {
+		t.Errorf("Load() without targets error = %v, want none", err)
+	}
+}
@@ -1,105 +1,173 @@
 package internal
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"openapi-generator/internal/parser"
 )
 
-// OverrideDefinition overrides the definition of a type.
-func OverrideDefinition(def *parser.Definition) *parser.Definition {
-	// Override definition.
-	switch def.Key {
-	case "Role":
-		def.Key = "MemberRole"
-		def.Description = "MemberRole represents a member role."
-	case "EntityType":
-		def.Key = "ViewEntityType"
-		def.Description = "ViewEntityType represents a view entity type."
-	case "ErrorCode":
-		def.Description = "ErrorCode represents an error code."
-	case "ErrorType":
-		def.Description = "ErrorType represents an error type."
-	case "RelationshipWithMember":
-		def.Description = "RelationshipWithMember represents a host-member relationship."
-	case "Colour":
-		def.Description = "Colour represents a recognised colour."
+// RenameDefinitions renames the document's definitions, and aliases their properties, as per the
+// given name extension e.g., "x-ts-name"; references to renamed definitions are updated accordingly.
+// The document is left untouched when two definitions would share a name.
+func RenameDefinitions(doc *parser.Document, ext string) error {
+	names := make(map[string]string)
+	for _, defs := range []map[string]*parser.Definition{doc.Definitions, doc.Responses} {
+		for k, def := range defs {
+			if name := def.Extensions.String(ext); name != "" && name != k {
+				names[k] = name
+			}
+		}
 	}
-	// Override properties.
-	for i, prop := range def.Properties {
-		def.Properties[i] = overrideDefinitionProperty(def.Key, prop)
+	rename := func(ref string) string {
+		if name, ok := names[ref]; ok {
+			return name
+		}
+		// Returned entities and union variants may be collections e.g., "Pet[]".
+		if elem := strings.TrimSuffix(ref, "[]"); elem != ref {
+			if name, ok := names[elem]; ok {
+				return name + "[]"
+			}
+		}
+		return ref
+	}
+	// Definitions are looked up by name, whether they're responses or not.
+	keys := make([]string, 0, len(doc.Definitions)+len(doc.Responses))
+	for _, defs := range []map[string]*parser.Definition{doc.Definitions, doc.Responses} {
+		for k := range defs {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	renamedFrom := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if other, ok := renamedFrom[rename(k)]; ok {
+			return fmt.Errorf("definitions '%s' and '%s' are both named '%s'", other, k, rename(k))
+		}
+		renamedFrom[rename(k)] = k
+	}
+	var renameProp func(prop *parser.DefinitionProperty, ownKey bool)
+	renameProp = func(prop *parser.DefinitionProperty, ownKey bool) {
+		if prop == nil {
+			return
+		}
+		prop.Ref = rename(prop.Ref)
+		// Only the properties of definitions are renamed, parameters keep their name; properties
+		// keep their key, as sent over the wire.
+		if name := prop.Extensions.String(ext); ownKey && name != "" && name != prop.Key {
+			prop.Alias = name
+		}
+		for _, item := range prop.PrefixItems {
+			renameProp(item, false)
+		}
+		renameProp(prop.AdditionalProperties, false)
 	}
 
-	return def
-}
-
-// ShakeModelDefinitions shakes definitions by moving those with the "enum" type.
-func ShakeModelDefinitions(m map[string]*parser.Definition, out map[string]*parser.Definition) {
-	for k, v := range m {
-		if v.Type == "enum" {
-			out[k] = v
-			delete(m, k)
+	for _, defs := range []map[string]*parser.Definition{doc.Definitions, doc.Responses} {
+		renamed := make(map[string]*parser.Definition, len(defs))
+		for k, def := range defs {
+			def.Key = rename(def.Key)
+			def.Ref = rename(def.Ref)
+			def.Returns = rename(def.Returns)
+			for _, refs := range [][]string{def.Extends, def.OneOf, def.AnyOf} {
+				for i := range refs {
+					refs[i] = rename(refs[i])
+				}
+			}
+			if def.Discriminator != nil {
+				for value, ref := range def.Discriminator.Mapping {
+					def.Discriminator.Mapping[value] = rename(ref)
+				}
+			}
+			if def.DynamicQuery != nil {
+				for i, ref := range def.DynamicQuery.CharacteristicKeys {
+					def.DynamicQuery.CharacteristicKeys[i] = rename(ref)
+				}
+			}
+			for _, prop := range def.Properties {
+				renameProp(prop, true)
+			}
+			renameProp(def.AdditionalProperties, false)
+			renamed[rename(k)] = def
+		}
+		for k := range defs {
+			delete(defs, k)
+		}
+		for k, def := range renamed {
+			defs[k] = def
+		}
+	}
+	for _, path := range doc.Paths {
+		for _, param := range path.Parameters {
+			renameProp(param, false)
+		}
+		for _, resp := range path.Responses {
+			resp.Ref = rename(resp.Ref)
+			renameProp(resp.Schema, false)
 		}
 	}
+	return nil
 }
 
-// ShakeResponseBodyDefinitions shakes definitions by moving those with keys containing
-// "ResponseBody".
-func ShakeResponseBodyDefinitions(m map[string]*parser.Definition, out map[string]*parser.Definition) {
-	for k, v := range m {
-		if strings.Contains(k, "ResponseBody") {
-			out[k] = v
-			delete(m, k)
+// ApplyOverrides sets the given extension on the document's definitions and properties, as per the
+// given overrides; definitions are keyed by name e.g., "Role", and their properties by
+// "{Definition}.{property}" e.g., "Member.created_at".
+func ApplyOverrides(doc *parser.Document, overrides map[string]string, ext string) error {
+	for k, v := range overrides {
+		defKey, propKey := k, ""
+		if i := strings.Index(k, "."); i >= 0 {
+			defKey, propKey = k[:i], k[i+1:]
+		}
+		def, ok := doc.Definitions[defKey]
+		if !ok {
+			def, ok = doc.Responses[defKey]
+		}
+		if !ok {
+			return fmt.Errorf("override '%s': unknown definition '%s'", k, defKey)
+		}
+		if propKey == "" {
+			def.Extensions = setExtension(def.Extensions, ext, v)
+			continue
+		}
+		found := false
+		for _, prop := range def.Properties {
+			if prop.Key == propKey {
+				prop.Extensions = setExtension(prop.Extensions, ext, v)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("override '%s': unknown property '%s' of '%s'", k, propKey, defKey)
 		}
 	}
+	return nil
 }
 
-// ShakeRequestBodyDefinitions shakes definitions by moving those with keys containing
-// "RequestBody".
-func ShakeRequestBodyDefinitions(m map[string]*parser.Definition, out map[string]*parser.Definition) {
-	for k, v := range m {
-		if strings.Contains(k, "RequestBody") {
-			out[k] = v
-			delete(m, k)
-		}
+// setExtension sets the given extension's value, allocating the extensions if need be.
+func setExtension(e parser.Extensions, name, value string) parser.Extensions {
+	if e == nil {
+		e = make(parser.Extensions)
 	}
+	e[name] = value
+	return e
 }
 
-// overrideDefinitionProperty overrides the definition of a property.
-func overrideDefinitionProperty(pKey string, prop *parser.DefinitionProperty) *parser.DefinitionProperty {
-	switch {
-	case strings.Contains(pKey, "DynamicQueryFilterRole") && prop.Key == "value":
-		prop.Ref = "MemberRole"
-	case prop.Ref == "Role":
-		prop.Ref = "MemberRole"
-	case prop.Ref == "EntityType":
-		prop.Ref = "ViewEntityType"
-	case prop.Ref != "":
-		switch prop.Type {
-		case "ImageFallback":
-			prop.Type = "Colour"
-		}
-	case prop.Key == "changed_by_self":
-		prop.Description = "Whether the whereabouts were updated by the current user."
-	case prop.Key == "address":
+// SplitDefinitions returns the given definitions split into models, enumerations and request
+// bodies i.e., those whose key ends with "RequestBody"; the given map is left untouched.
+func SplitDefinitions(m map[string]*parser.Definition) (models, enums, reqBodies map[string]*parser.Definition) {
+	models = make(map[string]*parser.Definition)
+	enums = make(map[string]*parser.Definition)
+	reqBodies = make(map[string]*parser.Definition)
+	for k, v := range m {
 		switch {
-		case strings.Contains(pKey, "Accommodation"):
-			prop.Description = "The accommodation's address."
-		case strings.Contains(pKey, "Organisation"):
-			prop.Description = "The organisation's address."
-		case strings.Contains(pKey, "Household"):
-			prop.Description = "The household's address."
-		}
-	case prop.Key == "country_code" && IsStandardModel(pKey):
-		prop.Key = "country"
-		prop.Type = "Country"
-		prop.Description = "The entity's country details."
-	case strings.HasSuffix(prop.Key, "_at") && !strings.Contains(pKey, "DynamicQuery"):
-		prop.Type = "ExtendedDate"
-	case prop.Key == "whereabouts":
-		prop.Description = "The member's whereabouts."
-	case prop.Key == "creator":
-		prop.Description = "The entity's creator."
+		case strings.HasSuffix(k, "RequestBody"):
+			reqBodies[k] = v
+		case v.Type == "enum":
+			enums[k] = v
+		default:
+			models[k] = v
+		}
 	}
-	return prop
+	return models, enums, reqBodies
 }
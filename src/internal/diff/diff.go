@@ -0,0 +1,285 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openapi-generator/internal/parser"
+)
+
+// Change represents a difference between two versions of a document.
+type Change struct {
+	// Whether the change breaks the clients generated from the former version.
+	Breaking bool
+	// The changed element's location e.g., "GET /members/{id}" or "definitions/Member".
+	Location string
+	// The change's description.
+	Message string
+}
+
+func (c *Change) String() string {
+	if c.Breaking {
+		return fmt.Sprintf("breaking: %s: %s", c.Location, c.Message)
+	}
+	return fmt.Sprintf("%s: %s", c.Location, c.Message)
+}
+
+// Documents returns the changes between the given former and latter versions of a document,
+// sorted by location.
+func Documents(from, to *parser.Document) []*Change {
+	changes := make([]*Change, 0)
+	changes = append(changes, diffPaths(from.Paths, to.Paths)...)
+	changes = append(changes, diffDefinitions("definitions/", from.Definitions, to.Definitions)...)
+	changes = append(changes, diffDefinitions("responses/", from.Responses, to.Responses)...)
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Location != changes[j].Location {
+			return changes[i].Location < changes[j].Location
+		}
+		return changes[i].Message < changes[j].Message
+	})
+	return changes
+}
+
+// HasBreaking checks whether any of the given changes is breaking.
+func HasBreaking(changes []*Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPaths returns the changes between the given operations.
+func diffPaths(from, to map[string]*parser.Path) []*Change {
+	changes := make([]*Change, 0)
+	for k, fromPath := range from {
+		toPath, ok := to[k]
+		if !ok {
+			changes = append(changes, &Change{Breaking: true, Location: k, Message: "operation removed"})
+			continue
+		}
+		if fromPath.Operation != toPath.Operation {
+			changes = append(changes, &Change{
+				Breaking: true,
+				Location: k,
+				Message:  fmt.Sprintf("operation identifier changed from '%s' to '%s'", fromPath.Operation, toPath.Operation),
+			})
+		}
+		changes = append(changes, diffParameters(k, fromPath.Parameters, toPath.Parameters)...)
+		changes = append(changes, diffSuccessResponses(k, fromPath, toPath)...)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			changes = append(changes, &Change{Location: k, Message: "operation added"})
+		}
+	}
+	return changes
+}
+
+// diffParameters returns the changes between the given parameters of the operation at the given
+// location.
+func diffParameters(location string, from, to []*parser.DefinitionProperty) []*Change {
+	key := func(param *parser.DefinitionProperty) string {
+		return param.In + " parameter '" + param.Key + "'"
+	}
+	toByKey := make(map[string]*parser.DefinitionProperty, len(to))
+	for _, param := range to {
+		toByKey[key(param)] = param
+	}
+	fromByKey := make(map[string]*parser.DefinitionProperty, len(from))
+	changes := make([]*Change, 0)
+	for _, fromParam := range from {
+		fromByKey[key(fromParam)] = fromParam
+		toParam, ok := toByKey[key(fromParam)]
+		if !ok {
+			changes = append(changes, &Change{Breaking: true, Location: location, Message: key(fromParam) + " removed"})
+			continue
+		}
+		changes = append(changes, diffProperty(location, key(fromParam), fromParam, toParam)...)
+	}
+	for _, toParam := range to {
+		if _, ok := fromByKey[key(toParam)]; !ok {
+			changes = append(changes, &Change{
+				Breaking: toParam.Required,
+				Location: location,
+				Message:  describeAddition(key(toParam), toParam),
+			})
+		}
+	}
+	return changes
+}
+
+// diffSuccessResponses returns the change between the success responses of the given operations,
+// if any.
+func diffSuccessResponses(location string, from, to *parser.Path) []*Change {
+	fromResp, toResp := from.SuccessResponse(), to.SuccessResponse()
+	if fromResp == nil || toResp == nil {
+		if (fromResp == nil) != (toResp == nil) {
+			return []*Change{{Breaking: true, Location: location, Message: "success response changed"}}
+		}
+		return nil
+	}
+	fromType, toType := describeResponse(fromResp), describeResponse(toResp)
+	if fromType == toType {
+		return nil
+	}
+	return []*Change{{
+		Breaking: true,
+		Location: location,
+		Message:  fmt.Sprintf("success response changed from '%s' to '%s'", fromType, toType),
+	}}
+}
+
+// describeResponse describes the type of the given response.
+func describeResponse(resp *parser.PathResponse) string {
+	switch {
+	case resp.Ref != "":
+		return resp.Ref
+	case resp.Schema != nil:
+		return describeType(resp.Schema)
+	default:
+		return "none"
+	}
+}
+
+// diffDefinitions returns the changes between the given definitions, whose location is prefixed
+// with the given prefix.
+func diffDefinitions(prefix string, from, to map[string]*parser.Definition) []*Change {
+	changes := make([]*Change, 0)
+	for k, fromDef := range from {
+		location := prefix + k
+		toDef, ok := to[k]
+		if !ok {
+			changes = append(changes, &Change{Breaking: true, Location: location, Message: "definition removed"})
+			continue
+		}
+		if fromDef.Type != toDef.Type {
+			changes = append(changes, &Change{
+				Breaking: true,
+				Location: location,
+				Message:  fmt.Sprintf("type changed from '%s' to '%s'", fromDef.Type, toDef.Type),
+			})
+			continue
+		}
+		changes = append(changes, diffEnumEntries(location, fromDef.EnumEntries, toDef.EnumEntries)...)
+		changes = append(changes, diffProperties(location, fromDef.Properties, toDef.Properties)...)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			changes = append(changes, &Change{Location: prefix + k, Message: "definition added"})
+		}
+	}
+	return changes
+}
+
+// diffEnumEntries returns the changes between the given enumeration entries.
+func diffEnumEntries(location string, from, to []string) []*Change {
+	changes := make([]*Change, 0)
+	for _, removed := range difference(from, to) {
+		changes = append(changes, &Change{Breaking: true, Location: location, Message: fmt.Sprintf("enum entry '%s' removed", removed)})
+	}
+	for _, added := range difference(to, from) {
+		changes = append(changes, &Change{Location: location, Message: fmt.Sprintf("enum entry '%s' added", added)})
+	}
+	return changes
+}
+
+// diffProperties returns the changes between the given properties of the definition at the given
+// location.
+func diffProperties(location string, from, to []*parser.DefinitionProperty) []*Change {
+	toByKey := make(map[string]*parser.DefinitionProperty, len(to))
+	for _, prop := range to {
+		toByKey[prop.Key] = prop
+	}
+	fromByKey := make(map[string]*parser.DefinitionProperty, len(from))
+	changes := make([]*Change, 0)
+	for _, fromProp := range from {
+		fromByKey[fromProp.Key] = fromProp
+		name := "property '" + fromProp.Key + "'"
+		toProp, ok := toByKey[fromProp.Key]
+		if !ok {
+			changes = append(changes, &Change{Breaking: true, Location: location, Message: name + " removed"})
+			continue
+		}
+		changes = append(changes, diffProperty(location, name, fromProp, toProp)...)
+	}
+	for _, toProp := range to {
+		if _, ok := fromByKey[toProp.Key]; !ok {
+			changes = append(changes, &Change{
+				Breaking: toProp.Required,
+				Location: location,
+				Message:  describeAddition("property '"+toProp.Key+"'", toProp),
+			})
+		}
+	}
+	return changes
+}
+
+// diffProperty returns the changes between the given versions of the property, or parameter, of
+// the given name.
+func diffProperty(location, name string, from, to *parser.DefinitionProperty) []*Change {
+	changes := make([]*Change, 0)
+	if fromType, toType := describeType(from), describeType(to); fromType != toType {
+		changes = append(changes, &Change{
+			Breaking: true,
+			Location: location,
+			Message:  fmt.Sprintf("%s type changed from '%s' to '%s'", name, fromType, toType),
+		})
+	}
+	if !from.Required && to.Required {
+		changes = append(changes, &Change{Breaking: true, Location: location, Message: name + " is now required"})
+	}
+	if from.Required && !to.Required {
+		changes = append(changes, &Change{Location: location, Message: name + " is now optional"})
+	}
+	if from.Nullable && !to.Nullable {
+		changes = append(changes, &Change{Location: location, Message: name + " is no longer nullable"})
+	}
+	if !from.Nullable && to.Nullable {
+		changes = append(changes, &Change{Breaking: true, Location: location, Message: name + " is now nullable"})
+	}
+	return changes
+}
+
+// describeAddition describes the addition of the given property, or parameter, of the given name.
+func describeAddition(name string, prop *parser.DefinitionProperty) string {
+	if prop.Required {
+		return "required " + name + " added"
+	}
+	return name + " added"
+}
+
+// describeType describes the type of the given property e.g., "[]Member" or "map[string]integer".
+func describeType(prop *parser.DefinitionProperty) string {
+	switch {
+	case prop.AdditionalProperties != nil:
+		return "map[string]" + describeType(prop.AdditionalProperties)
+	case prop.Type == "array" && prop.Ref != "":
+		return "[]" + prop.Ref
+	case prop.Ref != "":
+		return prop.Ref
+	case len(prop.Union) > 1:
+		return strings.Join(prop.Union, "|")
+	case prop.Format != "":
+		return prop.Type + "(" + prop.Format + ")"
+	default:
+		return prop.Type
+	}
+}
+
+// difference returns the entries of the given slice which aren't part of the other.
+func difference(s, other []string) []string {
+	included := make(map[string]bool, len(other))
+	for _, entry := range other {
+		included[entry] = true
+	}
+	result := make([]string, 0)
+	for _, entry := range s {
+		if !included[entry] {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+package internal
+
+// The specification extensions honoured by the generators.
+const (
+	// ExtensionGoName renames a definition or property within generated Go code i.e., that of
+	// plugin targets whose files are suffixed with ".go".
+	ExtensionGoName = "x-go-name"
+	// ExtensionTSName renames a definition or property within generated typescript code.
+	ExtensionTSName = "x-ts-name"
+	// ExtensionTSType overrides a property's type within generated typescript code; the property
+	// is constructed as such e.g., "ExtendedDate".
+	ExtensionTSType = "x-ts-type"
+	// ExtensionEnumVarNames names an enumeration's entries, in order.
+	ExtensionEnumVarNames = "x-enum-varnames"
+	// ExtensionPaginated marks a response body as paginated.
+	ExtensionPaginated = "x-paginated"
+	// ExtensionParamAlias renames a path parameter within generated method signatures.
+	ExtensionParamAlias = "x-param-alias"
+)
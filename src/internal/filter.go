@@ -21,27 +21,44 @@ func FilterIntoModelsAndEnumKeys(m map[string]*parser.Definition) ([]string, []s
 }
 
 // FilterIntoValidationObjectMap filters the given map of paths into a map of definition
-// properties suitable for validation.
+// properties suitable for validation; request bodies, and the objects they nest, are looked up
+// from the given definitions.
 func FilterIntoValidationObjectMap(defs map[string]*parser.Definition, m map[string]*parser.Path) map[string][]*parser.DefinitionProperty {
 	validations := make(map[string][]*parser.DefinitionProperty, 0)
-	for _, v := range m {
+	// Paths are visited in order, so that operations sharing their identifier are resolved alike.
+	for _, k := range SortMapKeysAlphabetically(m) {
+		v := m[k]
 		// Check if the path is suitable.
 		if !IsSuitedForAPIMethod(v.Parameters) {
 			continue
 		}
 
+		// Form payloads are validated from their parameters.
+		formEligibleProps := make([]*parser.DefinitionProperty, 0, len(v.Parameters))
+		for _, param := range FilterParameters(v.Parameters, "formData") {
+			if IsPropSuitableForValidation(param.Type) {
+				formEligibleProps = append(formEligibleProps, param)
+			}
+		}
+		if len(formEligibleProps) > 0 {
+			validations[v.Operation] = formEligibleProps
+		}
+
 		for _, param := range v.Parameters {
-			if param.In == "path" {
+			if param.In != "body" {
 				continue
 			}
 			// param.Name = Body i.e., the request body.
-			body := defs[param.Ref]
+			body, ok := defs[param.Ref]
+			if !ok {
+				continue
+			}
 			validationEligibleProps := make([]*parser.DefinitionProperty, 0, len(v.Parameters))
 			for _, param = range body.Properties {
 				if param.In == "path" {
 					continue
 				}
-				if IsPropSuitableForValidation(param.Type) {
+				if IsPropSuitableForValidation(param.Type) || IsNestedObject(defs, param) {
 					validationEligibleProps = append(validationEligibleProps, param)
 				}
 			}
@@ -53,3 +70,25 @@ func FilterIntoValidationObjectMap(defs map[string]*parser.Definition, m map[str
 
 	return validations
 }
+
+// FilterParameters returns the given parameters with the given destination.
+func FilterParameters(props []*parser.DefinitionProperty, in string) []*parser.DefinitionProperty {
+	result := make([]*parser.DefinitionProperty, 0, len(props))
+	for _, prop := range props {
+		if prop.In == in {
+			result = append(result, prop)
+		}
+	}
+	return result
+}
+
+// ExcludeParameters returns the given parameters, except those with the given destination.
+func ExcludeParameters(props []*parser.DefinitionProperty, in string) []*parser.DefinitionProperty {
+	result := make([]*parser.DefinitionProperty, 0, len(props))
+	for _, prop := range props {
+		if prop.In != in {
+			result = append(result, prop)
+		}
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package ir
+
+// Version is the version of the intermediate representation; it's incremented whenever a change
+// to its shape would break its consumers e.g., a field is renamed or removed.
+const Version = 1
+
+// Document represents what is generated from a `parser.Document`: its types and operations,
+// sorted by name so that identical documents always yield identical representations.
+type Document struct {
+	// The representation's version i.e., `Version`.
+	Version int `json:"version"`
+	// The specification's version e.g., "2.0" or "3.0.3".
+	SpecVersion string `json:"specVersion"`
+	// The API's title.
+	Title string `json:"title,omitempty"`
+	// The API's version.
+	APIVersion string `json:"apiVersion,omitempty"`
+	// The API's host.
+	Host string `json:"host,omitempty"`
+	// The API's base path.
+	BasePath string `json:"basePath,omitempty"`
+	// The API's types, sorted by name.
+	Types []*Type `json:"types"`
+	// The API's named responses, sorted by name.
+	Responses []*Type `json:"responses"`
+	// The API's operations, sorted by path then HTTP method.
+	Operations []*Operation `json:"operations"`
+}
+
+// The kinds of `Type`.
+const (
+	// KindModel designates an object.
+	KindModel = "model"
+	// KindEnum designates an enumeration.
+	KindEnum = "enum"
+	// KindMap designates a dictionary, whose values are described by `Type.Values`.
+	KindMap = "map"
+	// KindUnion designates a polymorphic object, whose variants are listed by `Type.Union`.
+	KindUnion = "union"
+	// KindRequest designates an operation's request body.
+	KindRequest = "request"
+	// KindResponse designates an operation's response.
+	KindResponse = "response"
+)
+
+// Type represents a named type.
+type Type struct {
+	// The type's name.
+	Name string `json:"name"`
+	// The type's kind e.g., `KindModel`.
+	Kind string `json:"kind"`
+	// The type's description.
+	Description string `json:"description,omitempty"`
+	// The names of the types it extends (allOf).
+	Extends []string `json:"extends,omitempty"`
+	// The name of the type it aliases, if any.
+	Ref string `json:"ref,omitempty"`
+	// The type's properties, in the order of `parser.Definition.Properties`.
+	Properties []*Property `json:"properties,omitempty"`
+	// The type's entries (enumerations only).
+	Enum *Enum `json:"enum,omitempty"`
+	// The type's variants (unions only).
+	Union *Union `json:"union,omitempty"`
+	// The type's values, beyond its properties, when the type is a map.
+	Values *Property `json:"values,omitempty"`
+	// The name of the returned type, suffixed with "[]" for collections (responses only).
+	Returns string `json:"returns,omitempty"`
+	// The names of the characteristics of a dynamic query request, if it is one.
+	DynamicQuery []string `json:"dynamicQuery,omitempty"`
+	// The type's specification extensions.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Enum represents the entries of an enumeration.
+type Enum struct {
+	// The entries' type e.g., "string" or "integer".
+	Type string `json:"type"`
+	// The entries, in declaration order.
+	Values []string `json:"values"`
+}
+
+// Union represents the variants of a polymorphic type.
+type Union struct {
+	// Whether exactly one variant must match (oneOf); otherwise, at least one must (anyOf).
+	Exclusive bool `json:"exclusive"`
+	// The variants, either types' names or, for inline variants which aren't objects, schema types
+	// e.g., "string", "null" or "Pet[]".
+	Variants []string `json:"variants"`
+	// The property telling apart the variants, if any.
+	Discriminator string `json:"discriminator,omitempty"`
+	// The variants' names, by discriminating value.
+	Mapping map[string]string `json:"mapping,omitempty"`
+}
+
+// Property represents a property of a type, an operation's parameter, or a response's schema.
+type Property struct {
+	// The property's name.
+	Name string `json:"name,omitempty"`
+	// The property's type e.g., "string", "array" or "object".
+	Type string `json:"type,omitempty"`
+	// The property's format e.g., "date-time".
+	Format string `json:"format,omitempty"`
+	// The name of the type it references; for arrays, the items' type.
+	Ref string `json:"ref,omitempty"`
+	// Whether the referenced type is an enumeration, be it extracted from the property's own
+	// entries or declared as a type of its own.
+	Enum bool `json:"enum,omitempty"`
+	// The accepted types, when more than one non-null type is.
+	Union []string `json:"union,omitempty"`
+	// Whether the property is required.
+	Required bool `json:"required,omitempty"`
+	// Whether the property accepts null.
+	Nullable bool `json:"nullable,omitempty"`
+	// The property's constant value.
+	Const interface{} `json:"const,omitempty"`
+	// The property's tuple items.
+	Tuple []*Property `json:"tuple,omitempty"`
+	// The property's values, when the property is a map.
+	Values *Property `json:"values,omitempty"`
+	// The property's description.
+	Description string `json:"description,omitempty"`
+	// The property's example values.
+	Examples []interface{} `json:"examples,omitempty"`
+	// The property's validation rules.
+	Validation *Validation `json:"validation,omitempty"`
+	// The parameter's location e.g., "query" (parameters only).
+	In string `json:"in,omitempty"`
+	// The parameter's array serialization format e.g., "csv" (parameters only).
+	CollectionFormat string `json:"collectionFormat,omitempty"`
+	// The property's specification extensions.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Validation represents the validation rules of a property; unset rules are omitted.
+type Validation struct {
+	Pattern   string  `json:"pattern,omitempty"`
+	MinLength int     `json:"minLength,omitempty"`
+	MaxLength int     `json:"maxLength,omitempty"`
+	Min       float64 `json:"min,omitempty"`
+	Max       float64 `json:"max,omitempty"`
+	MinItems  int     `json:"minItems,omitempty"`
+	MaxItems  int     `json:"maxItems,omitempty"`
+}
+
+// Operation represents an API operation i.e., a path and HTTP method pair.
+type Operation struct {
+	// The operation's identifier, which API clients name their methods after.
+	ID string `json:"id"`
+	// The operation's HTTP method e.g., "GET".
+	Method string `json:"method"`
+	// The operation's path e.g., "/members/{id}".
+	Path string `json:"path"`
+	// The operation's summary.
+	Description string `json:"description,omitempty"`
+	// The operation's parameters, including its request body, in the order of
+	// `parser.Path.Parameters`.
+	Parameters []*Property `json:"parameters,omitempty"`
+	// The operation's responses, sorted by status code.
+	Responses []*Response `json:"responses,omitempty"`
+	// The operation's request media types.
+	Consumes []string `json:"consumes,omitempty"`
+	// The operation's response media types.
+	Produces []string `json:"produces,omitempty"`
+	// The operation's specification extensions.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Response represents an operation's response for a given status code.
+type Response struct {
+	// The response's status code e.g., "200" or "default".
+	Code string `json:"code"`
+	// The response's description.
+	Description string `json:"description,omitempty"`
+	// The name of the named response it references, if any.
+	Ref string `json:"ref,omitempty"`
+	// The response's schema, if any.
+	Schema *Property `json:"schema,omitempty"`
+}
@@ -0,0 +1,207 @@
+package ir
+
+import (
+	"sort"
+	"strings"
+
+	"openapi-generator/internal/parser"
+)
+
+// New returns the intermediate representation of the given document, which is left untouched.
+func New(doc *parser.Document) *Document {
+	result := &Document{
+		Version:     Version,
+		SpecVersion: doc.SpecVersion,
+		Host:        doc.Host,
+		BasePath:    doc.BasePath,
+		Types:       make([]*Type, 0, len(doc.Definitions)),
+		Responses:   make([]*Type, 0, len(doc.Responses)),
+		Operations:  make([]*Operation, 0, len(doc.Paths)),
+	}
+	if doc.Meta != nil {
+		result.Title = doc.Meta.Title
+		result.APIVersion = doc.Meta.Version
+	}
+	for _, k := range sortedKeys(doc.Definitions) {
+		result.Types = append(result.Types, newType(k, doc.Definitions[k], false))
+	}
+	for _, k := range sortedKeys(doc.Responses) {
+		result.Responses = append(result.Responses, newType(k, doc.Responses[k], true))
+	}
+	for _, path := range doc.Paths {
+		result.Operations = append(result.Operations, newOperation(path))
+	}
+	sort.Slice(result.Operations, func(i, j int) bool {
+		if result.Operations[i].Path != result.Operations[j].Path {
+			return result.Operations[i].Path < result.Operations[j].Path
+		}
+		return result.Operations[i].Method < result.Operations[j].Method
+	})
+	return result
+}
+
+// kindOf returns the kind of the given definition, stored under the given key.
+func kindOf(key string, def *parser.Definition, response bool) string {
+	switch {
+	case def.Type == "enum":
+		return KindEnum
+	case response || strings.HasSuffix(key, "ResponseBody"):
+		return KindResponse
+	case strings.HasSuffix(key, "RequestBody"):
+		return KindRequest
+	case len(def.OneOf) > 0 || len(def.AnyOf) > 0:
+		return KindUnion
+	case def.Type == "map":
+		return KindMap
+	default:
+		return KindModel
+	}
+}
+
+// newType returns the representation of the given definition, stored under the given key.
+func newType(key string, def *parser.Definition, response bool) *Type {
+	t := &Type{
+		Name:        key,
+		Kind:        kindOf(key, def, response),
+		Description: def.Description,
+		Extends:     copyStrings(def.Extends),
+		Ref:         def.Ref,
+		Properties:  newProperties(def.Properties),
+		Returns:     def.Returns,
+		Values:      newProperty(def.AdditionalProperties),
+		Extensions:  copyExtensions(def.Extensions),
+	}
+	if def.Type == "enum" {
+		t.Enum = &Enum{Type: def.EnumType, Values: copyStrings(def.EnumEntries)}
+		if t.Enum.Type == "" {
+			t.Enum.Type = "string"
+		}
+	}
+	if len(def.OneOf) > 0 || len(def.AnyOf) > 0 {
+		t.Union = &Union{Exclusive: len(def.OneOf) > 0, Variants: copyStrings(def.OneOf)}
+		if !t.Union.Exclusive {
+			t.Union.Variants = copyStrings(def.AnyOf)
+		}
+		if def.Discriminator != nil {
+			t.Union.Discriminator = def.Discriminator.PropertyName
+			if len(def.Discriminator.Mapping) > 0 {
+				t.Union.Mapping = make(map[string]string, len(def.Discriminator.Mapping))
+				for k, v := range def.Discriminator.Mapping {
+					t.Union.Mapping[k] = v
+				}
+			}
+		}
+	}
+	if def.DynamicQuery != nil && def.DynamicQuery.OK {
+		t.DynamicQuery = copyStrings(def.DynamicQuery.CharacteristicKeys)
+	}
+	return t
+}
+
+// newOperation returns the representation of the given operation.
+func newOperation(path *parser.Path) *Operation {
+	op := &Operation{
+		ID:          path.Operation,
+		Method:      strings.ToUpper(path.HTTPVerb),
+		Path:        path.Key,
+		Description: path.Description,
+		Parameters:  newProperties(path.Parameters),
+		Consumes:    copyStrings(path.Consumes),
+		Produces:    copyStrings(path.Produces),
+		Extensions:  copyExtensions(path.Extensions),
+	}
+	codes := make([]string, 0, len(path.Responses))
+	for code := range path.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		resp := path.Responses[code]
+		op.Responses = append(op.Responses, &Response{
+			Code:        code,
+			Description: resp.Description,
+			Ref:         resp.Ref,
+			Schema:      newProperty(resp.Schema),
+		})
+	}
+	return op
+}
+
+// newProperties returns the representations of the given properties, or nil if there are none.
+func newProperties(props []*parser.DefinitionProperty) []*Property {
+	if len(props) == 0 {
+		return nil
+	}
+	result := make([]*Property, 0, len(props))
+	for _, prop := range props {
+		result = append(result, newProperty(prop))
+	}
+	return result
+}
+
+// newProperty returns the representation of the given property, or nil if there is none.
+func newProperty(prop *parser.DefinitionProperty) *Property {
+	if prop == nil {
+		return nil
+	}
+	p := &Property{
+		Name:             prop.Key,
+		Type:             prop.Type,
+		Format:           prop.Format,
+		Ref:              prop.Ref,
+		Enum:             prop.Enum,
+		Union:            copyStrings(prop.Union),
+		Required:         prop.Required,
+		Nullable:         prop.Nullable,
+		Const:            prop.Const,
+		Tuple:            newProperties(prop.PrefixItems),
+		Values:           newProperty(prop.AdditionalProperties),
+		Description:      prop.Description,
+		Examples:         prop.Examples,
+		In:               prop.In,
+		CollectionFormat: prop.CollectionFormat,
+		Extensions:       copyExtensions(prop.Extensions),
+	}
+	if v := prop.Validation; v != nil && *v != (parser.DefinitionPropertyValidation{}) {
+		p.Validation = &Validation{
+			Pattern:   v.Pattern,
+			MinLength: v.MinLength,
+			MaxLength: v.MaxLength,
+			Min:       v.Min,
+			Max:       v.Max,
+			MinItems:  v.MinItems,
+			MaxItems:  v.MaxItems,
+		}
+	}
+	return p
+}
+
+// sortedKeys returns the keys of the given definitions, sorted alphabetically.
+func sortedKeys(defs map[string]*parser.Definition) []string {
+	keys := make([]string, 0, len(defs))
+	for k := range defs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copyStrings returns a copy of the given slice, or nil if it's empty.
+func copyStrings(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+// copyExtensions returns a shallow copy of the given extensions, or nil if there are none.
+func copyExtensions(e parser.Extensions) map[string]interface{} {
+	if len(e) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(e))
+	for k, v := range e {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package ir
+
+import (
+	"encoding/json"
+	"testing"
+
+	"openapi-generator/internal/parser"
+)
+
+const spec = `
+swagger: "2.0"
+info: {title: Acme, version: "1.2"}
+basePath: /v1
+paths:
+  /members/{member_id}:
+    get:
+      operationId: getMember
+      parameters:
+        - {name: member_id, in: path, required: true, type: string}
+      responses:
+        '200': {description: ok, schema: {$ref: "#/definitions/Member"}}
+        '404': {description: not found}
+definitions:
+  Role: {type: string, enum: [admin, member]}
+  Member:
+    type: object
+    required: [id]
+    properties:
+      id: {type: string, minLength: 1}
+      role: {$ref: "#/definitions/Role"}
+`
+
+func TestNew(t *testing.T) {
+	doc, err := parser.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(New(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Dumps are snapshot-tested by consumers: any change to this one must come with a new version.
+	want := `{"version":1,"specVersion":"2.0","title":"Acme","apiVersion":"1.2","basePath":"/v1",` +
+		`"types":[` +
+		`{"name":"Member","kind":"model","properties":[` +
+		`{"name":"id","type":"string","required":true,"validation":{"minLength":1}},` +
+		`{"name":"role","ref":"Role","enum":true}]},` +
+		`{"name":"Role","kind":"enum","enum":{"type":"string","values":["admin","member"]}}],` +
+		`"responses":[{"name":"GetMemberResponse","kind":"response","description":"ok","ref":"Member"}],` +
+		`"operations":[{"id":"getMember","method":"GET","path":"/members/{member_id}",` +
+		`"parameters":[{"name":"member_id","type":"string","required":true,"in":"path"}],` +
+		`"responses":[{"code":"200","description":"ok","ref":"GetMemberResponse","schema":{"ref":"Member"}},` +
+		`{"code":"404","description":"not found"}]}]}`
+	if got := string(b); got != want {
+		t.Errorf("New() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNewLeavesDocumentUntouched(t *testing.T) {
+	doc, err := parser.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := New(doc)
+	result.Types[1].Enum.Values[0] = "owner"
+	result.Operations[0].Parameters[0].Name = "id"
+	if got := doc.Definitions["Role"].EnumEntries[0]; got != "admin" {
+		t.Errorf("Role's first entry = %q, want admin", got)
+	}
+	if got := doc.Paths["GET /members/{member_id}"].Parameters[0].Key; got != "member_id" {
+		t.Errorf("getMember's parameter = %q, want member_id", got)
+	}
+}
@@ -0,0 +1,217 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"openapi-generator/internal/parser"
+)
+
+// Finding represents a problem reported by a rule.
+type Finding struct {
+	// The rule's name e.g., "path-params".
+	Rule string
+	// The faulty element's location e.g., "GET /members/{id}" or "definitions/Member".
+	Location string
+	// The problem's description.
+	Message string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.Location, f.Message, f.Rule)
+}
+
+// rule represents a check of a document.
+type rule func(doc *parser.Document) []*Finding
+
+// rules are the checks run by `Lint`.
+var rules = []rule{
+	lintPathParams,
+	lintOperationDescriptions,
+	lintSuccessResponses,
+	lintOperationIDs,
+	lintUnusedDefinitions,
+}
+
+// Lint checks the given document against every rule, and returns the findings sorted by location.
+func Lint(doc *parser.Document) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, r := range rules {
+		findings = append(findings, r(doc)...)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+		return findings[i].Message < findings[j].Message
+	})
+	return findings
+}
+
+// routeParamRegex matches the parameters of a route e.g., "{id}".
+var routeParamRegex = regexp.MustCompile(`{([^{}]+)}`)
+
+// lintPathParams reports route parameters without a path parameter, and vice versa.
+func lintPathParams(doc *parser.Document) []*Finding {
+	findings := make([]*Finding, 0)
+	for k, path := range doc.Paths {
+		declared := make(map[string]bool)
+		for _, param := range path.Parameters {
+			if param.In == "path" {
+				declared[param.Key] = true
+			}
+		}
+		inRoute := make(map[string]bool)
+		for _, match := range routeParamRegex.FindAllStringSubmatch(path.Key, -1) {
+			inRoute[match[1]] = true
+			if !declared[match[1]] {
+				findings = append(findings, &Finding{
+					Rule:     "path-params",
+					Location: k,
+					Message:  fmt.Sprintf("route parameter '%s' isn't declared", match[1]),
+				})
+			}
+		}
+		for name := range declared {
+			if !inRoute[name] {
+				findings = append(findings, &Finding{
+					Rule:     "path-params",
+					Location: k,
+					Message:  fmt.Sprintf("path parameter '%s' isn't part of the route", name),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// lintOperationDescriptions reports operations without a summary.
+func lintOperationDescriptions(doc *parser.Document) []*Finding {
+	findings := make([]*Finding, 0)
+	for k, path := range doc.Paths {
+		if path.Description == "" {
+			findings = append(findings, &Finding{
+				Rule:     "operation-description",
+				Location: k,
+				Message:  "operation has no summary",
+			})
+		}
+	}
+	return findings
+}
+
+// lintSuccessResponses reports operations without a success response.
+func lintSuccessResponses(doc *parser.Document) []*Finding {
+	findings := make([]*Finding, 0)
+	for k, path := range doc.Paths {
+		if path.SuccessResponse() == nil {
+			findings = append(findings, &Finding{
+				Rule:     "success-response",
+				Location: k,
+				Message:  "operation has neither a 2xx nor a default response",
+			})
+		}
+	}
+	return findings
+}
+
+// lintOperationIDs reports operations sharing their identifier, which generated clients name their
+// methods after.
+func lintOperationIDs(doc *parser.Document) []*Finding {
+	byID := make(map[string][]string)
+	for k, path := range doc.Paths {
+		byID[path.Operation] = append(byID[path.Operation], k)
+	}
+	findings := make([]*Finding, 0)
+	for id, keys := range byID {
+		if len(keys) < 2 {
+			continue
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			findings = append(findings, &Finding{
+				Rule:     "operation-id",
+				Location: k,
+				Message:  fmt.Sprintf("operation identifier '%s' is shared with %s", id, strings.Join(otherKeys(keys, k), ", ")),
+			})
+		}
+	}
+	return findings
+}
+
+// otherKeys returns the given keys, but the given one.
+func otherKeys(keys []string, k string) []string {
+	result := make([]string, 0, len(keys)-1)
+	for _, key := range keys {
+		if key != k {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// lintUnusedDefinitions reports definitions which are referenced neither by an operation, nor by a
+// response, nor by another definition.
+func lintUnusedDefinitions(doc *parser.Document) []*Finding {
+	used := make(map[string]bool)
+	var useProp func(prop *parser.DefinitionProperty)
+	useProp = func(prop *parser.DefinitionProperty) {
+		if prop == nil {
+			return
+		}
+		used[prop.Ref] = true
+		for _, item := range prop.PrefixItems {
+			useProp(item)
+		}
+		useProp(prop.AdditionalProperties)
+	}
+	useDef := func(def *parser.Definition) {
+		used[def.Ref] = true
+		used[strings.TrimSuffix(def.Returns, "[]")] = true
+		for _, refs := range [][]string{def.Extends, def.OneOf, def.AnyOf} {
+			for _, ref := range refs {
+				used[strings.TrimSuffix(ref, "[]")] = true
+			}
+		}
+		if def.Discriminator != nil {
+			for _, ref := range def.Discriminator.Mapping {
+				used[ref] = true
+			}
+		}
+		for _, prop := range def.Properties {
+			useProp(prop)
+		}
+		useProp(def.AdditionalProperties)
+	}
+	for _, def := range doc.Definitions {
+		useDef(def)
+	}
+	for _, def := range doc.Responses {
+		useDef(def)
+	}
+	for _, path := range doc.Paths {
+		for _, param := range path.Parameters {
+			useProp(param)
+		}
+		for _, resp := range path.Responses {
+			useProp(resp.Schema)
+		}
+	}
+
+	findings := make([]*Finding, 0)
+	for k := range doc.Definitions {
+		if !used[k] {
+			findings = append(findings, &Finding{
+				Rule:     "unused-definition",
+				Location: "definitions/" + k,
+				Message:  "definition is never referenced",
+			})
+		}
+	}
+	return findings
+}
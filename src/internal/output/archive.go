@@ -0,0 +1,157 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// archiveTime is the modification time of the archives' files, fixed so that identical files yield
+// identical archives.
+var archiveTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// archiveFS represents the file system of an archive, on disk; files are kept in memory until
+// closed, at which point the archive is written at once.
+type archiveFS struct {
+	*MemFS
+	// The archive's path e.g., "web-sdk.zip".
+	path string
+	// encode writes the archive of the given files to the given writer.
+	encode func(w io.Writer, files *MemFS) error
+}
+
+// newArchiveFS returns the file system of the archive at the given path, encoded as per the given
+// function.
+func newArchiveFS(path string, encode func(w io.Writer, files *MemFS) error) *archiveFS {
+	return &archiveFS{MemFS: NewMemFS(), path: path, encode: encode}
+}
+
+func (a *archiveFS) Close() error {
+	var buf bytes.Buffer
+	if err := a.encode(&buf, a.MemFS); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(a.path, buf.Bytes())
+}
+
+// writeZip writes the zip archive of the given files to the given writer.
+func writeZip(w io.Writer, files *MemFS) error {
+	zw := zip.NewWriter(w)
+	for _, name := range files.Names() {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveTime}
+		header.SetMode(0644)
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(files.files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarGz writes the gzip-compressed tar archive of the given files to the given writer.
+func writeTarGz(w io.Writer, files *MemFS) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, name := range files.Names() {
+		data := files.files[name]
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  archiveTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// readArchive returns the files of the archive at the given path, as per its extension; an
+// archive which doesn't exist holds no files.
+func readArchive(path string) (*MemFS, error) {
+	files := NewMemFS()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+
+	if strings.HasSuffix(path, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(rc)
+			_ = rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			_ = files.WriteFile(f.Name, content)
+		}
+		return files, nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		_ = files.WriteFile(header.Name, content)
+	}
+	return files, nil
+}
+
+// isArchive checks whether the given output is written as an archive.
+func isArchive(output string) bool {
+	for _, suffix := range []string{".zip", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(output, suffix) {
+			return true
+		}
+	}
+	return false
+}
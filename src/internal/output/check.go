@@ -0,0 +1,70 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+
+	"openapi-generator/internal/textdiff"
+)
+
+// Mismatch represents a generated file which differs from the one on disk.
+type Mismatch struct {
+	// The file's path e.g., "web-sdk/src/api/definitions/models.ts".
+	Path string
+	// The unified diff turning the file on disk into the generated one.
+	Diff string
+}
+
+// Compare compares the given generated files with those of the given output, be it a directory or
+// an archive (see `OpenFS`); the files which differ, are missing, or would be removed as no longer
+// generated (see `WriteFiles`), are returned as mismatches.
+func Compare(generated *MemFS, output string) ([]*Mismatch, error) {
+	var existing FS = &DirFS{Root: output}
+	if isArchive(output) {
+		files, err := readArchive(output)
+		if err != nil {
+			return nil, err
+		}
+		existing = files
+	}
+
+	mismatches := make([]*Mismatch, 0)
+	for _, name := range generated.Names() {
+		filePath := path.Join(filepath.ToSlash(output), name)
+		fromName := "a/" + filePath
+		before, err := existing.ReadFile(name)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fromName = "/dev/null"
+		case err != nil:
+			return nil, err
+		}
+		after, _ := generated.ReadFile(name)
+		if diff := textdiff.Unified(fromName, "b/"+filePath, string(before), string(after)); diff != "" {
+			mismatches = append(mismatches, &Mismatch{Path: filePath, Diff: diff})
+		}
+	}
+
+	former, err := ReadManifest(existing)
+	if err != nil {
+		return nil, err
+	}
+	current, err := ReadManifest(generated)
+	if err != nil {
+		return nil, err
+	}
+	stale, err := staleFiles(existing, former, current)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedPaths(stale) {
+		filePath := path.Join(filepath.ToSlash(output), name)
+		mismatches = append(mismatches, &Mismatch{
+			Path: filePath,
+			Diff: textdiff.Unified("a/"+filePath, "/dev/null", string(stale[name]), ""),
+		})
+	}
+	return mismatches, nil
+}
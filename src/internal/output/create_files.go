@@ -1,56 +1,91 @@
 package output
 
 import (
-	"errors"
-	"os"
+	"fmt"
+	"path"
 	"strings"
 
 	"openapi-generator/internal/slog"
 )
 
-// CreateFiles will create the given files.
-func CreateFiles(version, outDir string, files []*File, extn string, logger slog.Logger) error {
-	logger.SetPrefix("[output.CreateFiles] ")
-	logger.Println("Generating output files...")
+// RenderedFile represents a generated file, as written to the output directory.
+type RenderedFile struct {
+	// The file's path, relative to the output directory e.g., "definitions/models.ts".
+	Path string
+	// The file's content, watermark included.
+	Content []byte
+}
 
-	// Append path suffix if missing.
-	if outDir != "" && !strings.HasSuffix(outDir, "/") {
-		outDir += "/"
-	}
-	for _, fileToBeCreated := range files {
+// Render returns the given files as written to the output directory i.e., suffixed with the given
+// extension, and prepended with the given watermark.
+func Render(files []*File, extn string, watermark *Watermark) []*RenderedFile {
+	// The watermark is rendered once, so that every file holds the same one.
+	mark := watermark.String()
+
+	result := make([]*RenderedFile, 0, len(files))
+	for _, f := range files {
 		// Append path suffix if missing.
-		if fileToBeCreated.Directory != "" && !strings.HasSuffix(fileToBeCreated.Directory, "/") {
-			fileToBeCreated.Directory += "/"
-		}
-		// Create the file's directory if it doesn't exist.
-		if err := os.MkdirAll(outDir+fileToBeCreated.Directory, 0755); err != nil {
-			logger.Println("os.MkdirAll:", err)
-			return err
+		dir := f.Directory
+		if dir != "" && !strings.HasSuffix(dir, "/") {
+			dir += "/"
 		}
+		result = append(result, &RenderedFile{
+			Path:    dir + f.Name + extn,
+			Content: []byte(mark + f.Body),
+		})
+	}
+	return result
+}
+
+// WriteFiles writes the given files to the given file system, alongside their manifest; files whose
+// content is unchanged are left untouched, so as to preserve their modification time. The files
+// listed by the former manifest which are no longer generated are removed, unless altered since.
+func WriteFiles(fsys FS, files []*RenderedFile, logger slog.Logger) error {
+	logger.SetPrefix("[output.WriteFiles] ")
+	logger.Println("Generating output files...")
 
-		filePath := outDir + fileToBeCreated.Directory + fileToBeCreated.Name + extn
-		logger.Printf("Seen '%s'", filePath)
-
-		// Attempt to open the file; otherwise, create it.
-		f, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-		if err != nil {
-			if !errors.Is(err, os.ErrNotExist) {
-				logger.Println("os.OpenFile:", err)
-				return err
-			}
-			f, err = os.Create(filePath)
-			if err != nil {
-				logger.Println("os.Create:", err)
-				return err
-			}
+	// The manifest would be overwritten by the file, or the file by the manifest.
+	for _, f := range files {
+		if path.Clean(f.Path) == ManifestName {
+			return fmt.Errorf("output: '%s' is reserved for the manifest", f.Path)
 		}
-		if _, err = f.WriteString(getWatermark(version, extn) + fileToBeCreated.Body); err != nil {
+	}
+
+	former, err := ReadManifest(fsys)
+	if err != nil {
+		logger.Println("ReadManifest:", err)
+		return err
+	}
+	manifest := NewManifest(files)
+
+	for _, fileToBeCreated := range files {
+		if err = writeFile(fsys, fileToBeCreated, logger); err != nil {
 			return err
 		}
+	}
 
-		logger.Printf("Created '%s'", filePath)
-		_ = f.Close()
+	removed, err := removeStale(fsys, former, manifest)
+	for _, p := range removed {
+		logger.Printf("Removed '%s'", p)
+	}
+	if err != nil {
+		logger.Println("removeStale:", err)
+		return err
 	}
 
+	// The manifest is written last, so that the files it no longer lists are removed beforehand;
+	// were the generation interrupted, the former one would still list them.
+	return writeFile(fsys, &RenderedFile{Path: ManifestName, Content: manifest.Bytes()}, logger)
+}
+
+// writeFile writes the given file to the given file system, which leaves it untouched if its
+// content is unchanged.
+func writeFile(fsys FS, fileToBeCreated *RenderedFile, logger slog.Logger) error {
+	logger.Printf("Seen '%s'", fileToBeCreated.Path)
+	if err := fsys.WriteFile(fileToBeCreated.Path, fileToBeCreated.Content); err != nil {
+		logger.Println("WriteFile:", err)
+		return err
+	}
+	logger.Printf("Written '%s'", fileToBeCreated.Path)
 	return nil
 }
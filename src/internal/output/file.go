@@ -2,7 +2,7 @@ package output
 
 // File represents a file to be created by the generator.
 type File struct {
-	Name      string
-	Directory string
-	Body      string
+	Name      string `json:"name"`
+	Directory string `json:"directory,omitempty"`
+	Body      string `json:"body"`
 }
@@ -1,6 +1,9 @@
 package output
 
-import "sync"
+import (
+	"sort"
+	"sync"
+)
 
 type (
 	// FileMap represents a map of files.
@@ -11,7 +14,7 @@ type (
 		Add(f *File)
 		// Get safely gets a file from the map.
 		Get(name string) *File
-		// Range returns a slice of keys.
+		// Range returns a slice of keys, sorted alphabetically.
 		Range() []string
 	}
 
@@ -48,8 +51,9 @@ func (l *fileMap) Range() []string {
 	defer l.mu.Unlock()
 
 	keys := make([]string, 0, len(l.src))
-	for k, _ := range l.src {
+	for k := range l.src {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FS represents a writable file system, generated files are written to; paths are slash-separated,
+// and relative to its root e.g., "definitions/models.ts".
+type FS interface {
+	// ReadFile returns the content of the file at the given path; the error wraps `os.ErrNotExist`
+	// if there is none.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile atomically replaces the content of the file at the given path, creating it,
+	// alongside its directories, if need be; files whose content is unchanged may be left
+	// untouched, so as to preserve their modification time.
+	WriteFile(name string, data []byte) error
+	// Remove removes the file at the given path; the error wraps `os.ErrNotExist` if there is none.
+	Remove(name string) error
+	// Close completes the writes; it isn't called if the generation fails.
+	Close() error
+}
+
+// OpenFS returns the file system writing to the given output, as per its extension: a zip archive
+// for ".zip", a gzip-compressed tar archive for ".tar.gz" or ".tgz", and a directory otherwise.
+func OpenFS(output string) (FS, error) {
+	switch {
+	case strings.HasSuffix(output, ".zip"):
+		return newArchiveFS(output, writeZip), nil
+	case strings.HasSuffix(output, ".tar.gz"), strings.HasSuffix(output, ".tgz"):
+		return newArchiveFS(output, writeTarGz), nil
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return nil, err
+	}
+	return &DirFS{Root: output}, nil
+}
+
+// DirFS represents the file system of a directory, on disk.
+type DirFS struct {
+	// The directory's path e.g., "web-sdk/src/api".
+	Root string
+}
+
+func (d *DirFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(d.path(name))
+}
+
+func (d *DirFS) WriteFile(name string, data []byte) error {
+	p := d.path(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(p, data)
+}
+
+func (d *DirFS) Remove(name string) error {
+	p := d.path(name)
+	if err := os.Remove(p); err != nil {
+		return err
+	}
+	// The directories left empty are removed as well, up to the root.
+	root := filepath.Clean(d.Root)
+	for dir := filepath.Dir(p); len(dir) > len(root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (d *DirFS) Close() error {
+	return nil
+}
+
+// path returns the path, on disk, of the file at the given path.
+func (d *DirFS) path(name string) string {
+	return filepath.Join(d.Root, filepath.FromSlash(name))
+}
+
+// MemFS represents an in-memory file system.
+type MemFS struct {
+	files map[string][]byte
+}
+
+// NewMemFS returns an empty in-memory file system.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string][]byte)}
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	data, ok := m.files[path.Clean(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte) error {
+	m.files[path.Clean(name)] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	if _, ok := m.files[path.Clean(name)]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, path.Clean(name))
+	return nil
+}
+
+func (m *MemFS) Close() error {
+	return nil
+}
+
+// Names returns the paths of the files, sorted.
+func (m *MemFS) Names() []string {
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeFileAtomic replaces the content of the file at the given path, through a temporary file
+// renamed over it, so that an interrupted write never leaves it half-written; the file is left
+// untouched if its content is unchanged.
+func writeFileAtomic(p string, data []byte) error {
+	existing, err := os.ReadFile(p)
+	if err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// The temporary file is removed, unless renamed.
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
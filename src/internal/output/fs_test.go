@@ -0,0 +1,154 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFSRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"index.ts":              "export * from './api-client';\n",
+		"definitions/models.ts": "export class Member {}\n",
+		"definitions/empty.ts":  "",
+	}
+	tests := []struct {
+		name string
+		// The output, relative to a temporary directory; empty for an in-memory file system.
+		output string
+	}{
+		{name: "memory"},
+		{name: "directory", output: "web-sdk/src/api"},
+		{name: "zip", output: "web-sdk.zip"},
+		{name: "tar.gz", output: "web-sdk.tar.gz"},
+		{name: "tgz", output: "out/web-sdk.tgz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fsys FS = NewMemFS()
+			output := ""
+			if tt.output != "" {
+				output = filepath.Join(t.TempDir(), tt.output)
+				var err error
+				if fsys, err = OpenFS(output); err != nil {
+					t.Fatalf("OpenFS() error = %v", err)
+				}
+			}
+			for name, content := range files {
+				if err := fsys.WriteFile(name, []byte(content)); err != nil {
+					t.Fatalf("WriteFile(%s) error = %v", name, err)
+				}
+			}
+			// Files are overwritten, and removed, before being read back.
+			if err := fsys.WriteFile("index.ts", []byte("stale\n")); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.WriteFile("index.ts", []byte(files["index.ts"])); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.WriteFile("obsolete/enums.ts", []byte("export enum Role {}\n")); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.Remove("obsolete/enums.ts"); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if err := fsys.Remove("obsolete/enums.ts"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Remove() of a missing file error = %v, want os.ErrNotExist", err)
+			}
+			if err := fsys.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			// The files are read back from the output, as `Compare` does.
+			read := fsys
+			switch {
+			case isArchive(output):
+				var err error
+				if read, err = readArchive(output); err != nil {
+					t.Fatalf("readArchive() error = %v", err)
+				}
+			case output != "":
+				read = &DirFS{Root: output}
+				if _, err := os.Stat(filepath.Join(output, "obsolete")); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("Remove() left the emptied directory behind")
+				}
+			}
+			for name, content := range files {
+				got, err := read.ReadFile(name)
+				if err != nil {
+					t.Fatalf("ReadFile(%s) error = %v", name, err)
+				}
+				if string(got) != content {
+					t.Errorf("ReadFile(%s) = %q, want %q", name, got, content)
+				}
+			}
+			if _, err := read.ReadFile("obsolete/enums.ts"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("ReadFile() of a removed file error = %v, want os.ErrNotExist", err)
+			}
+		})
+	}
+}
+
+func TestArchiveReproducible(t *testing.T) {
+	for _, name := range []string{"web-sdk.zip", "web-sdk.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			archives := make([][]byte, 0, 2)
+			for i := 0; i < 2; i++ {
+				output := filepath.Join(t.TempDir(), name)
+				fsys, err := OpenFS(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Files are written in a different order each time.
+				names := []string{"a.ts", "b/c.ts"}
+				if i == 1 {
+					names[0], names[1] = names[1], names[0]
+				}
+				for _, n := range names {
+					if err = fsys.WriteFile(n, []byte(n)); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err = fsys.Close(); err != nil {
+					t.Fatal(err)
+				}
+				data, err := os.ReadFile(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				archives = append(archives, data)
+			}
+			if !bytes.Equal(archives[0], archives[1]) {
+				t.Error("identical files yield different archives")
+			}
+		})
+	}
+}
+
+func TestDirFSLeavesUnchangedFiles(t *testing.T) {
+	fsys := &DirFS{Root: t.TempDir()}
+	if err := fsys.WriteFile("models.ts", []byte("export class Member {}\n")); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(fsys.Root, "models.ts")
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(p, past, past); err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"export class Member {}\n", "export class Person {}\n"} {
+		if err := fsys.WriteFile("models.ts", []byte(content)); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only the changed content updates the file's modification time.
+		if unchanged := info.ModTime().Equal(past); unchanged != (content == "export class Member {}\n") {
+			t.Errorf("WriteFile(%q) modification time = %v, was %v", content, info.ModTime(), past)
+		}
+	}
+}
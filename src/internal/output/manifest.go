@@ -0,0 +1,149 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ManifestName is the name of the manifest, written alongside the generated files.
+const ManifestName = ".openapi-parser-manifest.json"
+
+// Manifest represents the list of the files generated in an output, so that those which are no
+// longer generated may be removed by the next generation.
+type Manifest struct {
+	// The generated files, sorted by path.
+	Files []*ManifestEntry `json:"files"`
+}
+
+// ManifestEntry represents a generated file, as listed by the manifest.
+type ManifestEntry struct {
+	// The file's path, relative to the output e.g., "definitions/models.ts".
+	Path string `json:"path"`
+	// The hash of the file's content e.g., "sha256:4f1c2a9b...".
+	Hash string `json:"hash"`
+}
+
+// NewManifest returns the manifest listing the given files.
+func NewManifest(files []*RenderedFile) *Manifest {
+	hashes := make(map[string]string, len(files))
+	for _, f := range files {
+		hashes[path.Clean(f.Path)] = contentHash(f.Content)
+	}
+	// The manifest doesn't list itself.
+	delete(hashes, ManifestName)
+
+	paths := make([]string, 0, len(hashes))
+	for p := range hashes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	m := &Manifest{Files: make([]*ManifestEntry, 0, len(hashes))}
+	for _, p := range paths {
+		m.Files = append(m.Files, &ManifestEntry{Path: p, Hash: hashes[p]})
+	}
+	return m
+}
+
+// Bytes returns the manifest, as written to the output.
+func (m *Manifest) Bytes() []byte {
+	b, _ := json.MarshalIndent(m, "", "  ")
+	return append(b, '\n')
+}
+
+// Stale returns the entries of the manifest which aren't listed by the given one.
+func (m *Manifest) Stale(current *Manifest) []*ManifestEntry {
+	listed := make(map[string]bool, len(current.Files))
+	for _, e := range current.Files {
+		listed[e.Path] = true
+	}
+	stale := make([]*ManifestEntry, 0)
+	for _, e := range m.Files {
+		if !listed[e.Path] {
+			stale = append(stale, e)
+		}
+	}
+	return stale
+}
+
+// ReadManifest returns the manifest of the given file system; an empty one if there is none.
+func ReadManifest(fsys FS) (*Manifest, error) {
+	data, err := fsys.ReadFile(ManifestName)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestName, err)
+	}
+	for _, e := range m.Files {
+		// Entries escaping the output, which the generator never writes, are rejected.
+		e.Path = path.Clean(e.Path)
+		if path.IsAbs(e.Path) || e.Path == ".." || strings.HasPrefix(e.Path, "../") {
+			return nil, fmt.Errorf("%s: invalid path '%s'", ManifestName, e.Path)
+		}
+	}
+	return m, nil
+}
+
+// staleFiles returns the contents of the files of the given file system listed by its former
+// manifest, but not by the current one, keyed by path; files altered since their generation, which
+// are left untouched, are omitted.
+func staleFiles(fsys FS, former, current *Manifest) (map[string][]byte, error) {
+	stale := make(map[string][]byte)
+	for _, e := range former.Stale(current) {
+		data, err := fsys.ReadFile(e.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if contentHash(data) == e.Hash {
+			stale[e.Path] = data
+		}
+	}
+	return stale, nil
+}
+
+// removeStale removes the stale files of the given file system (see `staleFiles`), returning their
+// paths.
+func removeStale(fsys FS, former, current *Manifest) ([]string, error) {
+	stale, err := staleFiles(fsys, former, current)
+	if err != nil {
+		return nil, err
+	}
+	removed := make([]string, 0, len(stale))
+	for _, p := range sortedPaths(stale) {
+		if err = fsys.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed = append(removed, p)
+	}
+	return removed, nil
+}
+
+// sortedPaths returns the given files' paths, sorted.
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// contentHash returns the hash of the given content e.g., "sha256:4f1c2a9b...".
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
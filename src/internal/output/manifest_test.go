@@ -0,0 +1,135 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"openapi-generator/internal/slog"
+)
+
+func TestWriteFilesRemovesStaleFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		// alter alters the stale file, "enums.ts", between both generations.
+		alter func(t *testing.T, fsys FS)
+		// The expected content of the stale file after the second generation; nil if removed.
+		want []byte
+	}{
+		{
+			name:  "unchanged",
+			alter: func(t *testing.T, fsys FS) {},
+		},
+		{
+			name: "modified",
+			alter: func(t *testing.T, fsys FS) {
+				if err := fsys.WriteFile("enums.ts", []byte("export enum Role { Custom }\n")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []byte("export enum Role { Custom }\n"),
+		},
+		{
+			name: "removed",
+			alter: func(t *testing.T, fsys FS) {
+				if err := fsys.Remove("enums.ts"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, kind := range []string{"memory", "directory"} {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				var fsys FS = NewMemFS()
+				if kind == "directory" {
+					fsys = &DirFS{Root: t.TempDir()}
+				}
+				logger := slog.NewLogger("")
+				if err := fsys.WriteFile("custom.ts", []byte("// Written by hand.\n")); err != nil {
+					t.Fatal(err)
+				}
+				first := []*RenderedFile{
+					{Path: "models.ts", Content: []byte("export class Member {}\n")},
+					{Path: "enums.ts", Content: []byte("export enum Role {}\n")},
+				}
+				if err := WriteFiles(fsys, first, logger); err != nil {
+					t.Fatalf("WriteFiles() error = %v", err)
+				}
+				tt.alter(t, fsys)
+				second := []*RenderedFile{
+					{Path: "models.ts", Content: []byte("export class Person {}\n")},
+				}
+				if err := WriteFiles(fsys, second, logger); err != nil {
+					t.Fatalf("WriteFiles() error = %v", err)
+				}
+
+				got, err := fsys.ReadFile("enums.ts")
+				switch {
+				case tt.want == nil && !errors.Is(err, os.ErrNotExist):
+					t.Errorf("stale file = %q, %v; want it removed", got, err)
+				case tt.want != nil && string(got) != string(tt.want):
+					t.Errorf("stale file = %q, %v; want %q", got, err, tt.want)
+				}
+				// Files never generated are left untouched, generated ones are up to date.
+				if got, _ := fsys.ReadFile("custom.ts"); string(got) != "// Written by hand.\n" {
+					t.Errorf("custom file = %q, want it untouched", got)
+				}
+				if got, _ := fsys.ReadFile("models.ts"); string(got) != "export class Person {}\n" {
+					t.Errorf("generated file = %q, want it regenerated", got)
+				}
+				m, err := ReadManifest(fsys)
+				if err != nil {
+					t.Fatalf("ReadManifest() error = %v", err)
+				}
+				if len(m.Files) != 1 || m.Files[0].Path != "models.ts" {
+					t.Errorf("manifest = %s, want models.ts only", m.Bytes())
+				}
+			})
+		}
+	}
+}
+
+// failingRemoveFS represents an in-memory file system whose files can't be removed.
+type failingRemoveFS struct {
+	*MemFS
+}
+
+func (f *failingRemoveFS) Remove(string) error {
+	return os.ErrPermission
+}
+
+func TestWriteFilesKeepsManifestOnFailure(t *testing.T) {
+	fsys := &failingRemoveFS{MemFS: NewMemFS()}
+	logger := slog.NewLogger("")
+	first := []*RenderedFile{
+		{Path: "models.ts", Content: []byte("export class Member {}\n")},
+		{Path: "enums.ts", Content: []byte("export enum Role {}\n")},
+	}
+	if err := WriteFiles(fsys, first, logger); err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	before, _ := fsys.ReadFile(ManifestName)
+
+	if err := WriteFiles(fsys, first[:1], logger); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("WriteFiles() error = %v, want os.ErrPermission", err)
+	}
+	// The former manifest still lists the stale file, so that the next generation removes it.
+	if after, _ := fsys.ReadFile(ManifestName); string(after) != string(before) {
+		t.Errorf("manifest = %s, want %s", after, before)
+	}
+}
+
+func TestWriteFilesRejectsManifest(t *testing.T) {
+	fsys := NewMemFS()
+	files := []*RenderedFile{
+		{Path: "models.ts", Content: []byte("export class Member {}\n")},
+		{Path: "./" + ManifestName, Content: []byte("{}\n")},
+	}
+	if err := WriteFiles(fsys, files, slog.NewLogger("")); err == nil {
+		t.Fatal("WriteFiles() error = nil, want the manifest's name reported")
+	}
+	if names := fsys.Names(); len(names) != 0 {
+		t.Errorf("WriteFiles() wrote %q, want nothing", names)
+	}
+}
@@ -1,31 +1,126 @@
 package output
 
 import (
-	"fmt"
 	"strings"
 	"time"
 )
 
-const (
-	watermarkMessage = "This file was generated by the BoardingHub OpenAPI Code Generator. Do not edit directly."
+// CommentStyle represents how comments are written within generated files.
+type CommentStyle struct {
+	// The comment's opening line e.g., "/**"; comments without one are made of line comments only.
+	Start string
+	// The prefix of each of the comment's lines e.g., " * ".
+	Line string
+	// The comment's closing line e.g., " */".
+	End string
+}
+
+var (
+	// CommentStyleJSDoc represents block comments as written in JavaScript and TypeScript.
+	CommentStyleJSDoc = CommentStyle{Start: "/**", Line: " * ", End: " */"}
+	// CommentStyleSlashes represents line comments starting with "//" e.g., in Go.
+	CommentStyleSlashes = CommentStyle{Line: "// "}
+	// CommentStyleHash represents line comments starting with "#" e.g., in Python.
+	CommentStyleHash = CommentStyle{Line: "# "}
 )
 
-func getWatermark(version, extn string) string {
-	datetime := time.Now().Format("Mon Jan 2 2006 15:04:05 MST")
-
-	switch extn {
-	case ".ts":
-		format := strings.TrimPrefix(`
-/**
- * %s
- *
- * 	- version: %s
- * 	- timestamp: %s
- */
-
-`, "\n")
-		return fmt.Sprintf(format, watermarkMessage, version, datetime)
-	default:
+// commentStyles are the comment styles, by name.
+var commentStyles = map[string]CommentStyle{
+	"jsdoc":   CommentStyleJSDoc,
+	"slashes": CommentStyleSlashes,
+	"hash":    CommentStyleHash,
+	"none":    {},
+}
+
+// commentStylesByExtension are the names of the comment styles conventional for files, by
+// extension.
+var commentStylesByExtension = map[string]string{
+	".js":    "jsdoc",
+	".ts":    "jsdoc",
+	".c":     "slashes",
+	".cpp":   "slashes",
+	".cs":    "slashes",
+	".dart":  "slashes",
+	".go":    "slashes",
+	".h":     "slashes",
+	".java":  "slashes",
+	".kt":    "slashes",
+	".php":   "slashes",
+	".rs":    "slashes",
+	".scala": "slashes",
+	".swift": "slashes",
+	".ex":    "hash",
+	".exs":   "hash",
+	".pl":    "hash",
+	".py":    "hash",
+	".r":     "hash",
+	".rb":    "hash",
+	".sh":    "hash",
+	".toml":  "hash",
+	".yaml":  "hash",
+	".yml":   "hash",
+}
+
+// CommentStyleNameByExtension returns the name of the comment style conventional for files of the
+// given extension e.g., "hash" for ".py", and whether there is one.
+func CommentStyleNameByExtension(ext string) (string, bool) {
+	name, ok := commentStylesByExtension[strings.ToLower(ext)]
+	return name, ok
+}
+
+// CommentStyleByName returns the comment style of the given name: "jsdoc", "slashes", "hash" or
+// "none", and whether there is one.
+func CommentStyleByName(name string) (CommentStyle, bool) {
+	style, ok := commentStyles[name]
+	return style, ok
+}
+
+// Comment returns the given lines as a comment of this style.
+func (s CommentStyle) Comment(lines ...string) string {
+	result := make([]string, 0, len(lines)+2)
+	if s.Start != "" {
+		result = append(result, s.Start)
+	}
+	for _, line := range lines {
+		result = append(result, strings.TrimRight(s.Line+line, " "))
+	}
+	if s.End != "" {
+		result = append(result, s.End)
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+// Watermark represents the watermark prepended to generated files.
+type Watermark struct {
+	// The watermark's text.
+	Message string
+	// The generator's version.
+	Version string
+	// The hash of the specification's files the files are generated from, of the generator's
+	// version, and of the target's configuration; identical inputs yield identical watermarks.
+	Hash string
+	// Whether the generation's timestamp is included, at the cost of every file changing on every
+	// generation.
+	Timestamp bool
+	// The style of the comments of the generated files.
+	Style CommentStyle
+}
+
+// String returns the watermark as a comment; files without comments have no watermark.
+func (w *Watermark) String() string {
+	if w == nil || w.Style.Line == "" {
 		return ""
 	}
+	lines := []string{
+		w.Message,
+		"",
+		"\t- version: " + w.Version,
+	}
+	if w.Hash != "" {
+		lines = append(lines, "\t- hash: "+w.Hash)
+	}
+	if w.Timestamp {
+		lines = append(lines, "\t- timestamp: "+time.Now().Format("Mon Jan 2 2006 15:04:05 MST"))
+	}
+	return w.Style.Comment(lines...) + "\n"
 }
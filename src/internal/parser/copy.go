@@ -0,0 +1,168 @@
+package parser
+
+// Copy returns a deep copy of the document, which may be altered without altering the document.
+func (d *Document) Copy() *Document {
+	if d == nil {
+		return nil
+	}
+	result := &Document{
+		SpecVersion: d.SpecVersion,
+		Host:        d.Host,
+		BasePath:    d.BasePath,
+		Definitions: copyDefinitions(d.Definitions),
+		Responses:   copyDefinitions(d.Responses),
+		Sources:     append([][]byte(nil), d.Sources...),
+	}
+	if d.Meta != nil {
+		meta := *d.Meta
+		result.Meta = &meta
+	}
+	if d.Paths != nil {
+		result.Paths = make(map[string]*Path, len(d.Paths))
+		for k, v := range d.Paths {
+			result.Paths[k] = v.Copy()
+		}
+	}
+	return result
+}
+
+// Copy returns a deep copy of the definition.
+func (d *Definition) Copy() *Definition {
+	if d == nil {
+		return nil
+	}
+	result := *d
+	result.Properties = copyProperties(d.Properties)
+	result.EnumEntries = copyStrings(d.EnumEntries)
+	result.Extends = copyStrings(d.Extends)
+	result.OneOf = copyStrings(d.OneOf)
+	result.AnyOf = copyStrings(d.AnyOf)
+	result.Extensions = d.Extensions.Copy()
+	result.AdditionalProperties = d.AdditionalProperties.Copy()
+	if d.DynamicQuery != nil {
+		dq := *d.DynamicQuery
+		dq.CharacteristicKeys = copyStrings(d.DynamicQuery.CharacteristicKeys)
+		result.DynamicQuery = &dq
+	}
+	if d.Discriminator != nil {
+		disc := *d.Discriminator
+		if d.Discriminator.Mapping != nil {
+			disc.Mapping = make(map[string]string, len(d.Discriminator.Mapping))
+			for k, v := range d.Discriminator.Mapping {
+				disc.Mapping[k] = v
+			}
+		}
+		result.Discriminator = &disc
+	}
+	return &result
+}
+
+// Copy returns a deep copy of the property.
+func (p *DefinitionProperty) Copy() *DefinitionProperty {
+	if p == nil {
+		return nil
+	}
+	result := *p
+	result.Union = copyStrings(p.Union)
+	result.Const = copyValue(p.Const)
+	result.PrefixItems = copyProperties(p.PrefixItems)
+	result.AdditionalProperties = p.AdditionalProperties.Copy()
+	if p.Examples != nil {
+		result.Examples = copyValue(p.Examples).([]interface{})
+	}
+	if p.Validation != nil {
+		validation := *p.Validation
+		result.Validation = &validation
+	}
+	result.Extensions = p.Extensions.Copy()
+	return &result
+}
+
+// Copy returns a deep copy of the operation.
+func (p *Path) Copy() *Path {
+	if p == nil {
+		return nil
+	}
+	result := *p
+	result.Parameters = copyProperties(p.Parameters)
+	result.Consumes = copyStrings(p.Consumes)
+	result.Produces = copyStrings(p.Produces)
+	result.Extensions = p.Extensions.Copy()
+	if p.Responses != nil {
+		result.Responses = make(map[string]*PathResponse, len(p.Responses))
+		for code, resp := range p.Responses {
+			if resp == nil {
+				result.Responses[code] = nil
+				continue
+			}
+			respCopy := *resp
+			respCopy.Schema = resp.Schema.Copy()
+			result.Responses[code] = &respCopy
+		}
+	}
+	return &result
+}
+
+// Copy returns a deep copy of the extensions.
+func (e Extensions) Copy() Extensions {
+	if e == nil {
+		return nil
+	}
+	result := make(Extensions, len(e))
+	for k, v := range e {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+// copyDefinitions returns a deep copy of the given definitions.
+func copyDefinitions(defs map[string]*Definition) map[string]*Definition {
+	if defs == nil {
+		return nil
+	}
+	result := make(map[string]*Definition, len(defs))
+	for k, v := range defs {
+		result[k] = v.Copy()
+	}
+	return result
+}
+
+// copyProperties returns a deep copy of the given properties.
+func copyProperties(props []*DefinitionProperty) []*DefinitionProperty {
+	if props == nil {
+		return nil
+	}
+	result := make([]*DefinitionProperty, 0, len(props))
+	for _, prop := range props {
+		result = append(result, prop.Copy())
+	}
+	return result
+}
+
+// copyStrings returns a copy of the given slice.
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
+
+// copyValue returns a deep copy of the given normalized value (see `normalizeValue`).
+func copyValue(v interface{}) interface{} {
+	switch vTyped := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(vTyped))
+		for k, val := range vTyped {
+			result[k] = copyValue(val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(vTyped))
+		for _, val := range vTyped {
+			result = append(result, copyValue(val))
+		}
+		return result
+	default:
+		return v
+	}
+}
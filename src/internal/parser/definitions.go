@@ -1,9 +1,10 @@
 package parser
 
 import (
+	"fmt"
 	"github.com/iancoleman/strcase"
 	"regexp"
-	"strconv"
+	"sort"
 )
 
 // Definition represents a type definition.
@@ -24,14 +25,54 @@ type Definition struct {
 	Ref string
 	// Whether the model is a dynamic query request.
 	DynamicQuery *DynamicQuery
+	// The model's parent definitions' reference keys (allOf).
+	Extends []string
+	// The model's variants' reference keys, of which exactly one must match (oneOf); inline variants
+	// which aren't objects are listed by type instead e.g., "string", "null" or "Pet[]".
+	OneOf []string
+	// The model's variants' reference keys, of which at least one must match (anyOf); listed as per
+	// `OneOf`.
+	AnyOf []string
+	// The model's discriminator (polymorphic models only).
+	Discriminator *Discriminator
+	// The model's values, beyond its properties, when the model is a map (additionalProperties);
+	// models made of values only are of type "map".
+	AdditionalProperties *DefinitionProperty
+	// The model's enum entries' type e.g., "integer" (enum only).
+	EnumType string
+	// The model's specification extensions.
+	Extensions Extensions
+}
+
+// Discriminator represents the property used to tell apart the variants of a polymorphic model.
+type Discriminator struct {
+	// The discriminating property's name.
+	PropertyName string
+	// The variants' reference keys, by discriminating value.
+	Mapping map[string]string
 }
 
 // DefinitionProperty represents a property of `Definition`.
 type DefinitionProperty struct {
-	// The property's name.
+	// The property's name, as sent over the wire.
 	Key string
+	// The property's name within generated code, when it differs from its key e.g., as per the
+	// "x-ts-name" extension.
+	Alias string
 	// The property's type.
 	Type string
+	// The property's types when more than one non-null type is accepted (OpenAPI 3.1 only).
+	Union []string
+	// Whether the property accepts null.
+	Nullable bool
+	// The property's constant value (OpenAPI 3.1 only).
+	Const interface{}
+	// The property's tuple items (OpenAPI 3.1 only).
+	PrefixItems []*DefinitionProperty
+	// The property's values, when the property is a map (additionalProperties).
+	AdditionalProperties *DefinitionProperty
+	// The property's example values.
+	Examples []interface{}
 	// The property's description.
 	Description string
 	// The property's definition reference key.
@@ -44,6 +85,16 @@ type DefinitionProperty struct {
 	Format string
 	// The parameter's destination.
 	In string
+	// The parameter's array serialization format (csv, ssv, tsv, pipes or multi).
+	CollectionFormat string
+	// Whether the property references an enumeration, be it extracted from its own entries or
+	// declared as a definition of its own.
+	Enum bool
+	// The property's specification extensions.
+	Extensions Extensions
+	// The property's rank within its definition, in declaration order; properties are otherwise
+	// sorted by name (definition properties only).
+	Order int
 }
 
 // DynamicQuery represents a dynamic query request.
@@ -56,8 +107,17 @@ type DynamicQuery struct {
 
 // enumToMap represents an enumeration to map into a `Definition`.
 type enumToMap struct {
+	Key        string
+	Type       string
+	Entries    []string
+	Extensions Extensions
+}
+
+// schemaToMap represents an inline object schema to map into a `Definition` of its own.
+type schemaToMap struct {
 	Key     string
-	Entries []string
+	Pointer string
+	Schema  Record
 }
 
 // DefinitionPropertyValidation represents the validation properties of a `DefinitionProperty`.
@@ -68,10 +128,10 @@ type DefinitionPropertyValidation struct {
 	MaxLength int
 	// The property's minimum length (string).
 	MinLength int
-	// The property's maximum (integer).
-	Max int
-	// The property's minimum (integer).
-	Min int
+	// The property's maximum (number).
+	Max float64
+	// The property's minimum (number).
+	Min float64
 	// The property's maximum items (slice).
 	MaxItems int
 	// The property's minimum items (slice).
@@ -86,130 +146,91 @@ var (
 	requestRegex = regexp.MustCompile(`((Create|Get|List|Update)[aA-zZ]+)?Request[Body]?`)
 )
 
-// parseIntoDefinitions maps swagger definitions into a new instance of `map[string]*Definition`,
-func parseIntoDefinitions(rawDefs map[string]interface{}) map[string]*Definition {
+// parseIntoDefinitions maps swagger definitions, found under the given JSON pointer, into a new
+// instance of `map[string]*Definition`.
+func parseIntoDefinitions(pc *parseContext, rawDefs map[string]interface{}, ptr string) map[string]*Definition {
 	defMap := make(map[string]*Definition)
 	enumsToMap := make([]*enumToMap, 0)
-	for k, v := range rawDefs {
+
+	// Definitions are mapped off a queue, to which inline object schemas are appended as they are
+	// found; keys are sorted so that the names given to the latter are stable.
+	queue := flattenDefs(pc, rawDefs, ptr)
+	// Reserve the declared keys, so that inline schemas never shadow them.
+	reserved := make(map[string]bool, len(queue))
+	for _, s := range queue {
+		reserved[s.Key] = true
+	}
+	for i := 0; i < len(queue); i++ {
+		k, vTyped, defPtr := queue[i].Key, queue[i].Schema, queue[i].Pointer
 		def := &Definition{
 			Key:  k,
 			Type: "object",
 		}
-		vTyped := v.(Record)
-		if val := vTyped["title"]; val != nil {
-			def.Description = val.(string)
+		def.Description = pc.stringAt(vTyped, "title", defPtr)
+		def.Extensions = parseExtensions(vTyped)
+		// Enumerations declared as definitions of their own; their entries' names are declared
+		// alongside them.
+		if entries, _, ok := parseEnumEntries(pc, vTyped, defPtr); ok {
+			def.Type = "enum"
+			def.EnumEntries = entries
+			def.EnumType, _, _ = pc.typeAt(vTyped, "type", defPtr)
+			defMap[k] = def
+			continue
 		}
-		required := make(map[string]bool)
-		if val := vTyped["required"]; val != nil {
-			if valTyped, ok := val.([]interface{}); ok {
-				for _, prop := range valTyped {
-					required[prop.(string)] = true
+		enums, schemas := parseIntoDefinitionProperties(pc, def, vTyped, defPtr, reserved)
+		enumsToMap = append(enumsToMap, enums...)
+		queue = append(queue, schemas...)
+		// Compositions.
+		if allOf, ok := pc.sliceAt(vTyped, "allOf", defPtr); ok {
+			for i, part := range allOf {
+				partPtr := pointerTo(defPtr, "allOf", i)
+				partTyped, ok := part.(Record)
+				if !ok {
+					pc.unexpected(partPtr, "an object", part)
+					continue
+				}
+				if partRef := pc.refAt(partTyped, "$ref", partPtr); partRef != "" {
+					def.Extends = append(def.Extends, partRef)
+					continue
 				}
-			} else {
-				required[val.(string)] = true
+				// Inline parts are merged into the definition.
+				enums, schemas := parseIntoDefinitionProperties(pc, def, partTyped, partPtr, reserved)
+				enumsToMap = append(enumsToMap, enums...)
+				queue = append(queue, schemas...)
 			}
 		}
-		if properties := vTyped["properties"]; properties != nil {
-			props := make([]*DefinitionProperty, 0)
-			if valTyped, ok := properties.(Record); ok {
-				for propName, propVal := range valTyped {
-					prop := &DefinitionProperty{
-						Key:        propName.(string),
-						Validation: &DefinitionPropertyValidation{},
-					}
-					// Checks if this property is marked as 'required'.
-					if _, ok := required[propName.(string)]; ok {
-						prop.Required = true
-					}
-					if propValTyped, ok := propVal.(Record); ok {
-						// Properties.
-						if propType := propValTyped["type"]; propType != nil {
-							prop.Type = propType.(string)
-						}
-						if propDesc := propValTyped["description"]; propDesc != nil {
-							prop.Description = extractDescription(propDesc.(string))
-						}
-						if propRef := propValTyped["$ref"]; propRef != nil {
-							// Strip away spec's prefix.
-							prop.Ref = toRef(propRef.(string))
-							// Account for dynamic query characteristics.
-							// Note: ref will always be set with a dynamic query.
-							if dq := def.DynamicQuery; dq != nil && dq.OK {
-								dq.CharacteristicKeys = append(dq.CharacteristicKeys, prop.Ref)
-							}
-						}
-						if propFormat := propValTyped["format"]; propFormat != nil {
-							prop.Format = propFormat.(string)
-						}
-						if propSchema := propValTyped["schema"]; propSchema != nil {
-							if propSchemaTyped, ok := propSchema.(Record); ok {
-								prop.Type = propSchemaTyped["type"].(string)
-							}
-						}
-						// Slices will have their own reference.
-						if propItems := propValTyped["items"]; propItems != nil {
-							if propItemsTyped, ok := propItems.(Record); ok {
-								if propItemsRef := propItemsTyped["$ref"]; propItemsRef != nil {
-									prop.Ref = toRef(propItemsRef.(string))
-								}
-								if propItemsType := propItemsTyped["type"]; propItemsType != nil {
-									prop.Ref = toRef(propItemsType.(string))
-								}
-							}
-						}
-						// Enumerations should be extracted into their own definitions,
-						// and properties should reference them.
-						if propEnum := propValTyped["enum"]; propEnum != nil {
-							enumEntries := make([]string, 0)
-							if propEnumTyped, ok := propEnum.([]interface{}); ok {
-								for _, entry := range propEnumTyped {
-									entryTyped, ok := entry.(string)
-									if !ok {
-										entryTyped = strconv.Itoa(entry.(int))
-									}
-									enumEntries = append(enumEntries, entryTyped)
-								}
-
-								key := strcase.ToCamel(prop.Key)
-								// Keys equal to "image_fallback" and "colour" will produce the same enum,
-								// but we are only interested in the misc.Colour enum.
-								if key != "ImageFallbackColourIdx" {
-									prop.Type = "" // Reset to follow ref.
-									prop.Ref = key
-									enumsToMap = append(enumsToMap, &enumToMap{
-										Key:     key,
-										Entries: enumEntries,
-									})
-								}
-							}
-						}
-						// Validation properties.
-						if propPattern := propValTyped["pattern"]; propPattern != nil {
-							prop.Validation.Pattern = propPattern.(string)
-						}
-						if propMinLength := propValTyped["minLength"]; propMinLength != nil {
-							prop.Validation.MinLength = propMinLength.(int)
-						}
-						if propMaxLength := propValTyped["maxLength"]; propMaxLength != nil {
-							prop.Validation.MaxLength = propMaxLength.(int)
-						}
-						if propMinItems := propValTyped["minItems"]; propMinItems != nil {
-							prop.Validation.MinItems = propMinItems.(int)
-						}
-						if propMaxItems := propValTyped["maxItems"]; propMaxItems != nil {
-							prop.Validation.MaxItems = propMaxItems.(int)
-						}
-						if propMax := propValTyped["maximum"]; propMax != nil {
-							prop.Validation.Max = propMax.(int)
-						}
-						if propMin := propValTyped["minimum"]; propMin != nil {
-							prop.Validation.Min = propMin.(int)
-						}
-					}
-					props = append(props, prop)
+		if _, ok := vTyped["oneOf"]; ok {
+			def.Type = "union"
+			var enums []*enumToMap
+			var schemas []*schemaToMap
+			def.OneOf, enums, schemas = parseVariants(pc, def, vTyped, "oneOf", defPtr, reserved)
+			enumsToMap = append(enumsToMap, enums...)
+			queue = append(queue, schemas...)
+		}
+		if _, ok := vTyped["anyOf"]; ok {
+			def.Type = "union"
+			var enums []*enumToMap
+			var schemas []*schemaToMap
+			def.AnyOf, enums, schemas = parseVariants(pc, def, vTyped, "anyOf", defPtr, reserved)
+			enumsToMap = append(enumsToMap, enums...)
+			queue = append(queue, schemas...)
+		}
+		// Maps keep track of their values' type, whose inline object schemas are hoisted as well.
+		if additional := vTyped["additionalProperties"]; additional != nil {
+			additionalPtr := pointerTo(defPtr, "additionalProperties")
+			if def.AdditionalProperties = parseAdditionalProperties(pc, additional, additionalPtr); def.AdditionalProperties != nil {
+				if additionalTyped, ok := additional.(Record); ok && isInlineObject(additionalTyped) {
+					def.AdditionalProperties.Type = ""
+					def.AdditionalProperties.Ref = reserveKey(reserved, k+"Value")
+					queue = append(queue, &schemaToMap{Key: def.AdditionalProperties.Ref, Pointer: additionalPtr, Schema: additionalTyped})
+				}
+				if def.Type == "object" && len(def.Properties) == 0 && len(def.Extends) == 0 {
+					def.Type = "map"
 				}
 			}
-			def.Properties = props
+		}
+		if discriminator := vTyped["discriminator"]; discriminator != nil {
+			def.Discriminator = parseDiscriminator(pc, discriminator, pointerTo(defPtr, "discriminator"))
 		}
 		// Assign definition.
 		defMap[k] = def
@@ -221,10 +242,518 @@ func parseIntoDefinitions(rawDefs map[string]interface{}) map[string]*Definition
 				defMap[enum.Key] = &Definition{
 					Key:         enum.Key,
 					EnumEntries: enum.Entries,
+					EnumType:    enum.Type,
 					Type:        "enum",
+					Extensions:  enum.Extensions,
 				}
 			}
 		}
 	}
+	resolveDiscriminators(defMap)
 	return defMap
 }
+
+// parseIntoDefinitionProperties maps the properties of the given schema, found under the given
+// JSON pointer, into the given definition, and returns the enumerations and inline object schemas
+// to be mapped into definitions of their own; the keys given to the latter are added to the
+// reserved keys.
+func parseIntoDefinitionProperties(
+	pc *parseContext, def *Definition, vTyped Record, ptr string, reserved map[string]bool,
+) ([]*enumToMap, []*schemaToMap) {
+	enumsToMap := make([]*enumToMap, 0)
+	schemasToMap := make([]*schemaToMap, 0)
+	required := make(map[string]bool)
+	switch val := vTyped["required"].(type) {
+	case nil:
+	case string:
+		required[val] = true
+	case []interface{}:
+		for i, prop := range val {
+			propTyped, ok := prop.(string)
+			if !ok {
+				pc.unexpected(pointerTo(ptr, "required", i), "a string", prop)
+				continue
+			}
+			required[propTyped] = true
+		}
+	default:
+		pc.unexpected(pointerTo(ptr, "required"), "an array", val)
+	}
+	properties, ok := pc.recordAt(vTyped, "properties", ptr)
+	if !ok {
+		return enumsToMap, schemasToMap
+	}
+	props := make([]*DefinitionProperty, 0, len(properties))
+	// Properties merged from several schemas (allOf) are ranked after those merged beforehand.
+	base := len(def.Properties)
+	for i, propKey := range sortedRecordKeys(properties) {
+		propName := fmt.Sprint(propKey)
+		propPtr := pointerTo(ptr, "properties", propName)
+		prop := &DefinitionProperty{
+			Key:        propName,
+			Validation: &DefinitionPropertyValidation{},
+			Order:      base + i,
+		}
+		if index, ok := pc.indexOf(propPtr); ok {
+			prop.Order = base + index
+		}
+		// Checks if this property is marked as 'required'.
+		if _, ok := required[propName]; ok {
+			prop.Required = true
+		}
+		propValTyped, ok := properties[propKey].(Record)
+		if !ok {
+			pc.unexpected(propPtr, "an object", properties[propKey])
+			continue
+		}
+		// Properties.
+		prop.Type, prop.Union, prop.Nullable = pc.typeAt(propValTyped, "type", propPtr)
+		if pc.boolAt(propValTyped, "nullable", propPtr) {
+			prop.Nullable = true
+		}
+		if propConst, ok := propValTyped["const"]; ok {
+			prop.Const = normalizeValue(propConst)
+			if prop.Type == "" {
+				prop.Type = typeOfValue(propConst)
+			}
+		}
+		if propPrefixItems, ok := pc.sliceAt(propValTyped, "prefixItems", propPtr); ok {
+			prop.Type = "array"
+			for i, item := range propPrefixItems {
+				prop.PrefixItems = append(prop.PrefixItems, parseSchemaType(pc, item, pointerTo(propPtr, "prefixItems", i)))
+			}
+		}
+		prop.Examples = parseExamples(propValTyped)
+		prop.Extensions = parseExtensions(propValTyped)
+		prop.Description = extractDescription(pc.stringAt(propValTyped, "description", propPtr))
+		if propRef := pc.refAt(propValTyped, "$ref", propPtr); propRef != "" {
+			prop.Ref = propRef
+			// Account for dynamic query characteristics.
+			// Note: ref will always be set with a dynamic query.
+			if dq := def.DynamicQuery; dq != nil && dq.OK {
+				dq.CharacteristicKeys = append(dq.CharacteristicKeys, prop.Ref)
+			}
+		}
+		prop.Format = pc.stringAt(propValTyped, "format", propPtr)
+		if propSchema, ok := pc.recordAt(propValTyped, "schema", propPtr); ok {
+			var nullable bool
+			prop.Type, prop.Union, nullable = pc.typeAt(propSchema, "type", pointerTo(propPtr, "schema"))
+			// Nullability declared through the "nullable" keyword is kept.
+			prop.Nullable = prop.Nullable || nullable
+		}
+		// Slices will have their own reference.
+		if propItems, ok := pc.recordAt(propValTyped, "items", propPtr); ok {
+			itemsPtr := pointerTo(propPtr, "items")
+			if propItemsRef := pc.refAt(propItems, "$ref", itemsPtr); propItemsRef != "" {
+				prop.Ref = propItemsRef
+			}
+			if itemsType, _, _ := pc.typeAt(propItems, "type", itemsPtr); itemsType != "" {
+				prop.Ref = toRef(itemsType)
+			}
+		}
+		// Enumerations should be extracted into their own definitions,
+		// and properties should reference them.
+		if enumEntries, nullable, ok := parseEnumEntries(pc, propValTyped, propPtr); ok {
+			// A null entry is expressed through the property's nullability instead.
+			if nullable {
+				prop.Nullable = true
+			}
+
+			// Enumerations are named after their property, unless named through the
+			// "x-enum-name" extension.
+			key := strcase.ToCamel(prop.Key)
+			if name := prop.Extensions.String("x-enum-name"); name != "" {
+				key = name
+			}
+			// The enumeration's own extensions, such as its entries' names, are declared alongside
+			// its entries; properties sharing an enumeration name it alike.
+			enumsToMap = append(enumsToMap, &enumToMap{
+				Key:        key,
+				Type:       prop.Type,
+				Entries:    enumEntries,
+				Extensions: prop.Extensions.withPrefix("x-enum-"),
+			})
+			prop.Type = "" // Reset to follow ref.
+			prop.Ref = key
+			prop.Enum = true
+		}
+		// Compositions of a single referenced schema reference it.
+		if ref, nullable, ok := singleRef(propValTyped); ok {
+			prop.Type = ""
+			prop.Ref = ref
+			prop.Nullable = prop.Nullable || nullable
+		} else if propItemsTyped, ok := propValTyped["items"].(Record); ok {
+			if ref, _, ok := singleRef(propItemsTyped); ok {
+				prop.Ref = ref
+			}
+		}
+		// Inline object and composed schemas, either of the property itself or of its items, should
+		// be hoisted into their own definitions, and properties should reference them.
+		if isInlineObject(propValTyped) {
+			prop.Type = "" // Reset to follow ref.
+			prop.Ref = reserveKey(reserved, def.Key+strcase.ToCamel(prop.Key))
+			schemasToMap = append(schemasToMap, &schemaToMap{Key: prop.Ref, Pointer: propPtr, Schema: propValTyped})
+		} else if propItemsTyped, ok := propValTyped["items"].(Record); ok && isInlineObject(propItemsTyped) {
+			prop.Type = "array"
+			prop.Ref = reserveKey(reserved, def.Key+strcase.ToCamel(prop.Key)+"Item")
+			schemasToMap = append(schemasToMap, &schemaToMap{Key: prop.Ref, Pointer: pointerTo(propPtr, "items"), Schema: propItemsTyped})
+		} else if propAdditional := propValTyped["additionalProperties"]; propAdditional != nil {
+			// Maps keep track of their values' type, whose inline object schemas are hoisted
+			// as well.
+			additionalPtr := pointerTo(propPtr, "additionalProperties")
+			if prop.AdditionalProperties = parseAdditionalProperties(pc, propAdditional, additionalPtr); prop.AdditionalProperties != nil {
+				prop.Type = "object"
+				if propAdditionalTyped, ok := propAdditional.(Record); ok && isInlineObject(propAdditionalTyped) {
+					prop.AdditionalProperties.Type = ""
+					prop.AdditionalProperties.Ref = reserveKey(reserved, def.Key+strcase.ToCamel(prop.Key)+"Value")
+					schemasToMap = append(schemasToMap, &schemaToMap{Key: prop.AdditionalProperties.Ref, Pointer: additionalPtr, Schema: propAdditionalTyped})
+				}
+			}
+		}
+		// Validation properties.
+		prop.Validation = parseValidation(pc, propValTyped, propPtr)
+		props = append(props, prop)
+	}
+	def.Properties = append(def.Properties, props...)
+	return enumsToMap, schemasToMap
+}
+
+// parseEnumEntries maps the entries of the given schema's enumeration, found under the given JSON
+// pointer, into strings; null entries are left out, and reported through the returned nullability.
+// @returns (entries, nullable, ok): ok -> whether the schema is an enumeration
+func parseEnumEntries(pc *parseContext, schema Record, ptr string) ([]string, bool, bool) {
+	enum, ok := pc.sliceAt(schema, "enum", ptr)
+	if !ok {
+		return nil, false, false
+	}
+	entries := make([]string, 0, len(enum))
+	nullable := false
+	for i, entry := range enum {
+		switch entryTyped := entry.(type) {
+		case nil:
+			nullable = true
+		case string:
+			entries = append(entries, entryTyped)
+		case int, int64, uint64, float64, bool:
+			entries = append(entries, fmt.Sprint(entryTyped))
+		default:
+			pc.unexpected(pointerTo(ptr, "enum", i), "a scalar", entry)
+		}
+	}
+	return entries, nullable, true
+}
+
+// parseValidation maps the validation keywords of the given schema, found under the given JSON
+// pointer, into a new instance of `DefinitionPropertyValidation`.
+func parseValidation(pc *parseContext, schema Record, ptr string) *DefinitionPropertyValidation {
+	return &DefinitionPropertyValidation{
+		Pattern:   pc.stringAt(schema, "pattern", ptr),
+		MinLength: pc.intAt(schema, "minLength", ptr),
+		MaxLength: pc.intAt(schema, "maxLength", ptr),
+		MinItems:  pc.intAt(schema, "minItems", ptr),
+		MaxItems:  pc.intAt(schema, "maxItems", ptr),
+		Max:       pc.numberAt(schema, "maximum", ptr),
+		Min:       pc.numberAt(schema, "minimum", ptr),
+	}
+}
+
+// parseVariants maps the variants of a oneOf or anyOf composition, found under the given key of
+// the given definition's schema, into their reference keys. Inline variants are referenced by their
+// type when they don't need a definition e.g., "string", "null" or "string[]"; enumerations and
+// object schemas are otherwise returned, to be mapped into definitions of their own, named after
+// the definition e.g., "PetVariant2".
+func parseVariants(
+	pc *parseContext, def *Definition, schema Record, key, ptr string, reserved map[string]bool,
+) ([]string, []*enumToMap, []*schemaToMap) {
+	enumsToMap := make([]*enumToMap, 0)
+	schemasToMap := make([]*schemaToMap, 0)
+	vTyped, ok := pc.sliceAt(schema, key, ptr)
+	if !ok {
+		return nil, enumsToMap, schemasToMap
+	}
+	variants := make([]string, 0, len(vTyped))
+	for i, variant := range vTyped {
+		variantPtr := pointerTo(ptr, key, i)
+		variantTyped, ok := variant.(Record)
+		if !ok {
+			pc.unexpected(variantPtr, "an object", variant)
+			continue
+		}
+		if variantRef := pc.refAt(variantTyped, "$ref", variantPtr); variantRef != "" {
+			variants = append(variants, variantRef)
+			continue
+		}
+		name := fmt.Sprint(def.Key, "Variant", i+1)
+		variantType, variantUnion, nullable := pc.typeAt(variantTyped, "type", variantPtr)
+		if pc.boolAt(variantTyped, "nullable", variantPtr) {
+			nullable = true
+		}
+		switch {
+		case variantTyped["enum"] != nil:
+			entries, enumNullable, _ := parseEnumEntries(pc, variantTyped, variantPtr)
+			nullable = nullable || enumNullable
+			name = reserveKey(reserved, name)
+			enumsToMap = append(enumsToMap, &enumToMap{
+				Key:        name,
+				Type:       variantType,
+				Entries:    entries,
+				Extensions: parseExtensions(variantTyped),
+			})
+			variants = append(variants, name)
+		case isInlineObject(variantTyped):
+			name = reserveKey(reserved, name)
+			schemasToMap = append(schemasToMap, &schemaToMap{Key: name, Pointer: variantPtr, Schema: variantTyped})
+			variants = append(variants, name)
+		case variantType == "array":
+			itemsPtr := pointerTo(variantPtr, "items")
+			items, _ := pc.recordAt(variantTyped, "items", variantPtr)
+			itemsType, _, _ := pc.typeAt(items, "type", itemsPtr)
+			switch itemsRef := pc.refAt(items, "$ref", itemsPtr); {
+			case itemsRef != "":
+				variants = append(variants, itemsRef+"[]")
+			case items != nil && isInlineObject(items):
+				name = reserveKey(reserved, name+"Item")
+				schemasToMap = append(schemasToMap, &schemaToMap{Key: name, Pointer: itemsPtr, Schema: items})
+				variants = append(variants, name+"[]")
+			case itemsType != "":
+				variants = append(variants, itemsType+"[]")
+			default:
+				variants = append(variants, "array")
+			}
+		case len(variantUnion) > 0:
+			variants = append(variants, variantUnion...)
+		case variantType != "" && variantType != "null":
+			variants = append(variants, variantType)
+		}
+		if nullable {
+			variants = append(variants, "null")
+		}
+	}
+	return variants, enumsToMap, schemasToMap
+}
+
+// parseDiscriminator maps a discriminator, expressed either as a property name (Swagger 2) or as
+// an object (OpenAPI 3), into a new instance of `Discriminator`.
+func parseDiscriminator(pc *parseContext, v interface{}, ptr string) *Discriminator {
+	discriminator := &Discriminator{
+		Mapping: make(map[string]string),
+	}
+	switch vTyped := v.(type) {
+	case string:
+		discriminator.PropertyName = vTyped
+	case Record:
+		discriminator.PropertyName = pc.stringAt(vTyped, "propertyName", ptr)
+		if mapping, ok := pc.recordAt(vTyped, "mapping", ptr); ok {
+			for value, ref := range mapping {
+				refTyped, ok := ref.(string)
+				if !ok {
+					pc.unexpected(pointerTo(ptr, "mapping", value), "a string", ref)
+					continue
+				}
+				discriminator.Mapping[fmt.Sprint(value)] = toRef(refTyped)
+			}
+		}
+	default:
+		pc.unexpected(ptr, "a string or an object", v)
+	}
+	return discriminator
+}
+
+// resolveDiscriminators completes the discriminators' mappings; variants missing from a mapping
+// are discriminated by their key, as per the specification.
+//
+// Variants are the definitions listed under oneOf/anyOf or, for Swagger 2 discriminators, the
+// definitions extending the discriminated definition.
+func resolveDiscriminators(defMap map[string]*Definition) {
+	for _, def := range defMap {
+		if def.Discriminator == nil {
+			continue
+		}
+		variants := append(append([]string{}, def.OneOf...), def.AnyOf...)
+		if len(variants) == 0 {
+			for k, child := range defMap {
+				for _, parent := range child.Extends {
+					if parent == def.Key {
+						variants = append(variants, k)
+					}
+				}
+			}
+		}
+		mapped := make(map[string]bool, len(def.Discriminator.Mapping))
+		for _, ref := range def.Discriminator.Mapping {
+			mapped[ref] = true
+		}
+		for _, variant := range variants {
+			// Variants which are types rather than definitions e.g., "null", can't be discriminated.
+			if _, ok := defMap[variant]; ok && !mapped[variant] {
+				def.Discriminator.Mapping[variant] = variant
+			}
+		}
+	}
+}
+
+// resolveEnums marks the properties of the given document referencing an enumeration as such,
+// whether the enumeration was extracted from their own entries or declared as a definition.
+func resolveEnums(doc *Document) {
+	var resolve func(prop *DefinitionProperty)
+	resolve = func(prop *DefinitionProperty) {
+		if prop == nil {
+			return
+		}
+		if def, ok := doc.Definitions[prop.Ref]; ok && def.Type == "enum" {
+			prop.Enum = true
+		}
+		for _, item := range prop.PrefixItems {
+			resolve(item)
+		}
+		resolve(prop.AdditionalProperties)
+	}
+	for _, defs := range []map[string]*Definition{doc.Definitions, doc.Responses} {
+		for _, def := range defs {
+			for _, prop := range def.Properties {
+				resolve(prop)
+			}
+			resolve(def.AdditionalProperties)
+		}
+	}
+	for _, path := range doc.Paths {
+		for _, param := range path.Parameters {
+			resolve(param)
+		}
+		for _, resp := range path.Responses {
+			resolve(resp.Schema)
+		}
+	}
+}
+
+// isInlineObject checks whether the given schema is an object or composed schema declared in
+// place, rather than referenced.
+func isInlineObject(schema Record) bool {
+	if schema["$ref"] != nil {
+		return false
+	}
+	if _, _, ok := singleRef(schema); ok {
+		return false
+	}
+	return schema["properties"] != nil || schema["allOf"] != nil || schema["oneOf"] != nil || schema["anyOf"] != nil
+}
+
+// singleRef returns the reference key of the given composed schema, when it's made of a single
+// referenced schema e.g., `allOf: [{$ref: '#/definitions/Pet'}]`, the usual way to describe a
+// reference alongside other keywords; oneOf/anyOf may list null as well, in which case the schema
+// is nullable.
+// @returns (ref, nullable, ok): ok -> whether the schema is made of a single referenced schema
+func singleRef(schema Record) (string, bool, bool) {
+	if schema["properties"] != nil {
+		return "", false, false
+	}
+	ref, nullable := "", false
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		parts, ok := schema[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, part := range parts {
+			partTyped, ok := part.(Record)
+			if !ok {
+				return "", false, false
+			}
+			partRef, isRef := partTyped["$ref"].(string)
+			switch {
+			case isRef && ref == "":
+				ref = toRef(partRef)
+			case !isRef && key != "allOf" && isNullSchema(partTyped):
+				nullable = true
+			default:
+				return "", false, false
+			}
+		}
+	}
+	return ref, nullable, ref != ""
+}
+
+// isNullSchema checks whether the given schema only accepts null e.g., `type: "null"`.
+func isNullSchema(schema Record) bool {
+	t, _, nullable := parseType(schema["type"])
+	return t == "null" && nullable
+}
+
+// reserveKey reserves the given definition key, suffixed with a number should it be taken already.
+func reserveKey(reserved map[string]bool, key string) string {
+	result := key
+	for i := 2; reserved[result]; i++ {
+		result = fmt.Sprint(key, i)
+	}
+	reserved[result] = true
+	return result
+}
+
+// flattenDefs returns the given definitions, found under the given JSON pointer, alongside the
+// definitions nested under their `$defs` keyword (OpenAPI 3.1 only), sorted by key; as both are
+// registered under their own key, nested definitions named after another definition are reported.
+func flattenDefs(pc *parseContext, rawDefs map[string]interface{}, ptr string) []*schemaToMap {
+	result := make(map[string]*schemaToMap, len(rawDefs))
+	// add registers the given definition, returning nil when it can't be.
+	add := func(k string, v interface{}, defPtr string) *schemaToMap {
+		vTyped, ok := v.(Record)
+		if !ok {
+			pc.unexpected(defPtr, "an object", v)
+			return nil
+		}
+		if other, ok := result[k]; ok {
+			pc.errorf(defPtr, "definition '%s' is already declared at %s", k, other.Pointer)
+			return nil
+		}
+		result[k] = &schemaToMap{Key: k, Pointer: defPtr, Schema: vTyped}
+		return result[k]
+	}
+	var flatten func(schema *schemaToMap)
+	flatten = func(schema *schemaToMap) {
+		nestedPtr := pointerTo(schema.Pointer, "$defs")
+		nested, _ := pc.recordAt(schema.Schema, "$defs", schema.Pointer)
+		for _, nestedKey := range sortedRecordKeys(nested) {
+			k := fmt.Sprint(nestedKey)
+			if def := add(k, nested[nestedKey], pointerTo(nestedPtr, k)); def != nil {
+				flatten(def)
+			}
+		}
+	}
+	// Top-level definitions are registered first, so that nested ones are reported when colliding.
+	keys := make([]string, 0, len(rawDefs))
+	for k := range rawDefs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	topLevel := make([]*schemaToMap, 0, len(keys))
+	for _, k := range keys {
+		if def := add(k, rawDefs[k], pointerTo(ptr, k)); def != nil {
+			topLevel = append(topLevel, def)
+		}
+	}
+	for _, def := range topLevel {
+		flatten(def)
+	}
+
+	keys = make([]string, 0, len(result))
+	for k := range result {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	schemas := make([]*schemaToMap, 0, len(keys))
+	for _, k := range keys {
+		schemas = append(schemas, result[k])
+	}
+	return schemas
+}
+
+// sortedRecordKeys returns the keys of the given record, sorted alphabetically.
+func sortedRecordKeys(rec Record) []interface{} {
+	keys := make([]interface{}, 0, len(rec))
+	for k := range rec {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
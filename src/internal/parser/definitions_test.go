@@ -0,0 +1,240 @@
+package parser
+
+import "testing"
+
+func TestNullableProperties(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Member:
+      type: object
+      properties:
+        name: {type: string}
+        nickname: {type: [string, "null"]}
+        email: {type: string, nullable: true}
+        phone: {nullable: true, schema: {type: string}}
+        role: {type: string, enum: [admin, user, null]}
+        team: {anyOf: [{$ref: '#/components/schemas/Team'}, {type: "null"}]}
+    Team:
+      type: object
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"name": false, "nickname": true, "email": true, "phone": true, "role": true, "team": true}
+	for _, prop := range doc.Definitions["Member"].Properties {
+		if prop.Nullable != want[prop.Key] {
+			t.Errorf("property '%s' nullable = %t, want %t", prop.Key, prop.Nullable, want[prop.Key])
+		}
+	}
+}
+
+func TestJSONSchema2020Properties(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Member:
+      type: object
+      properties:
+        score: {type: [integer, string]}
+        kind: {const: member}
+        point:
+          prefixItems:
+            - type: number
+            - $ref: '#/components/schemas/Member/$defs/Unit'
+        id: {type: string, examples: [mbr_1, mbr_2]}
+      $defs:
+        Unit:
+          type: object
+          properties:
+            name: {type: string}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := make(map[string]*DefinitionProperty)
+	for _, prop := range doc.Definitions["Member"].Properties {
+		props[prop.Key] = prop
+	}
+	if got := props["score"].Union; len(got) != 2 || got[0] != "integer" || got[1] != "string" {
+		t.Errorf("score union = %q, want [integer string]", got)
+	}
+	if got := props["kind"]; got.Const != "member" || got.Type != "string" {
+		t.Errorf("kind const, type = %v, %q; want member, string", got.Const, got.Type)
+	}
+	if got := props["point"]; got.Type != "array" || len(got.PrefixItems) != 2 || got.PrefixItems[1].Ref != "Unit" {
+		t.Errorf("point = %+v, want a tuple of a number and a Unit", got)
+	}
+	if got := props["id"].Examples; len(got) != 2 {
+		t.Errorf("id examples = %v, want 2", got)
+	}
+	// Definitions nested under $defs are registered under their own key.
+	if _, ok := doc.Definitions["Unit"]; !ok {
+		t.Error("NewDocument() lacks the nested definition 'Unit'")
+	}
+}
+
+func TestCompositions(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+openapi: 3.0.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Entity:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+    ViewEntity:
+      oneOf:
+        - $ref: '#/components/schemas/GroupView'
+        - $ref: '#/components/schemas/MemberView'
+      discriminator:
+        propertyName: entity_type
+        mapping:
+          group: '#/components/schemas/GroupView'
+    GroupView:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            entity_type: {type: string}
+            name: {type: string}
+    MemberView:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            entity_type: {type: string}
+    Anything:
+      anyOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: string
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Definitions["GroupView"]; len(got.Extends) != 1 || got.Extends[0] != "Entity" || len(got.Properties) != 2 {
+		t.Errorf("GroupView = %+v, want Entity extended by 2 properties", got)
+	}
+	view := doc.Definitions["ViewEntity"]
+	if len(view.OneOf) != 2 || view.OneOf[0] != "GroupView" || view.OneOf[1] != "MemberView" {
+		t.Errorf("ViewEntity oneOf = %q, want [GroupView MemberView]", view.OneOf)
+	}
+	if d := view.Discriminator; d == nil || d.PropertyName != "entity_type" || d.Mapping["group"] != "GroupView" {
+		t.Errorf("ViewEntity discriminator = %+v, want entity_type mapping group to GroupView", d)
+	}
+	// Inline variants which aren't objects are listed by type.
+	if got := doc.Definitions["Anything"].AnyOf; len(got) != 2 || got[0] != "Entity" || got[1] != "string" {
+		t.Errorf("Anything anyOf = %q, want [Entity string]", got)
+	}
+}
+
+func TestInlineObjectsHoisted(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member:
+    type: object
+    required: [profile]
+    properties:
+      profile:
+        type: object
+        required: [name]
+        properties:
+          name: {type: string}
+          location:
+            type: object
+            properties:
+              lat: {type: number}
+      tags:
+        type: array
+        items:
+          type: object
+          properties:
+            label: {type: string}
+  MemberProfile:
+    type: object
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := make(map[string]*DefinitionProperty)
+	for _, prop := range doc.Definitions["Member"].Properties {
+		props[prop.Key] = prop
+	}
+	// Hoisted objects are named after their path, unless the name is taken.
+	if got := props["profile"]; got.Ref != "MemberProfile2" || !got.Required {
+		t.Errorf("profile = %+v, want a required reference to MemberProfile2", got)
+	}
+	if got := props["tags"]; got.Type != "array" || got.Ref != "MemberTagsItem" {
+		t.Errorf("tags = %+v, want an array of MemberTagsItem", got)
+	}
+	for k, want := range map[string]int{"MemberProfile2": 2, "MemberProfile2Location": 1, "MemberTagsItem": 1} {
+		def, ok := doc.Definitions[k]
+		if !ok {
+			t.Errorf("NewDocument() lacks the hoisted definition '%s'", k)
+			continue
+		}
+		if def.Type != "object" || len(def.Properties) != want {
+			t.Errorf("'%s' = %+v, want an object of %d properties", k, def, want)
+		}
+	}
+}
+
+func TestMaps(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Cat:
+      type: object
+    Scores:
+      type: object
+      additionalProperties: {type: integer}
+    Anything:
+      type: object
+      additionalProperties: true
+    Labels:
+      type: object
+      properties:
+        id: {type: string}
+      additionalProperties: {$ref: '#/components/schemas/Cat'}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key string
+		// The expected definition's type, and values' type or reference.
+		wantType   string
+		wantValues string
+	}{
+		{key: "Scores", wantType: "map", wantValues: "integer"},
+		{key: "Anything", wantType: "map"},
+		// Models holding properties alongside their values remain objects.
+		{key: "Labels", wantType: "object", wantValues: "Cat"},
+	}
+	for _, tt := range tests {
+		def := doc.Definitions[tt.key]
+		if def == nil || def.AdditionalProperties == nil {
+			t.Errorf("'%s' = %+v, want a map", tt.key, def)
+			continue
+		}
+		values := def.AdditionalProperties.Type + def.AdditionalProperties.Ref
+		if def.Type != tt.wantType || values != tt.wantValues {
+			t.Errorf("'%s' type, values = %q, %q; want %q, %q", tt.key, def.Type, values, tt.wantType, tt.wantValues)
+		}
+	}
+}
@@ -1,16 +1,48 @@
 package parser
 
 import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"gopkg.in/yaml.v2"
 )
 
 type preParsedDocument struct {
+	Swagger     string                 `yaml:"swagger"`
+	OpenAPI     string                 `yaml:"openapi"`
 	Meta        *DocumentMeta          `yaml:"info"`
 	Definitions map[string]interface{} `yaml:"definitions"`
 	Paths       map[string]interface{} `yaml:"paths"`
-	Responses map[string]interface{} `yaml:"responses"`
-	Host      string                 `yaml:"host"`
-	BasePath  string                 `yaml:"basePath"`
+	Responses   map[string]interface{} `yaml:"responses"`
+	Parameters  map[string]interface{} `yaml:"parameters"`
+	Host        string                 `yaml:"host"`
+	BasePath    string                 `yaml:"basePath"`
+	Consumes    []string               `yaml:"consumes"`
+	Produces    []string               `yaml:"produces"`
+	Components  *preParsedComponents   `yaml:"components"`
+	Servers     []*preParsedServer     `yaml:"servers"`
+}
+
+// preParsedComponents represents the reusable objects of an OpenAPI 3 document.
+type preParsedComponents struct {
+	Schemas       map[string]interface{} `yaml:"schemas"`
+	Responses     map[string]interface{} `yaml:"responses"`
+	Parameters    map[string]interface{} `yaml:"parameters"`
+	RequestBodies map[string]interface{} `yaml:"requestBodies"`
+}
+
+// preParsedServer represents a server of an OpenAPI 3 document.
+type preParsedServer struct {
+	URL       string                           `yaml:"url"`
+	Variables map[string]*preParsedServerValue `yaml:"variables"`
+}
+
+// preParsedServerValue represents a server variable of an OpenAPI 3 document.
+type preParsedServerValue struct {
+	Default string `yaml:"default"`
 }
 
 // DocumentMeta represents the document's metadata.
@@ -21,6 +53,8 @@ type DocumentMeta struct {
 
 // Document represents a parsed document.
 type Document struct {
+	// The specification's version (e.g. "2.0", "3.0.3").
+	SpecVersion string
 	// The document's metadata.
 	Meta *DocumentMeta
 	// The API's hosts.
@@ -33,20 +67,171 @@ type Document struct {
 	Responses map[string]*Definition
 	// The API's paths.
 	Paths map[string]*Path
+	// The raw content of the files the document is parsed from, the main one first, followed by
+	// those it references, in order of resolution.
+	Sources [][]byte `json:"-"`
 }
 
-// NewDocument returns a new instance of `Document`.
+// NewDocument returns a new instance of `Document`; relative references to other files are
+// resolved from the working directory.
+//
+// Both Swagger 2 and OpenAPI 3 documents are supported; the latter's constructs are mapped
+// onto the same model. Malformed values don't stop the parsing: every problem found is returned
+// at once, as `ParseErrors`.
 func NewDocument(b []byte) (*Document, error) {
+	return newDocument(b, "")
+}
+
+// NewDocumentFromFile returns a new instance of `Document` from the given file; relative
+// references to other files are resolved from the file's directory.
+func NewDocumentFromFile(name string) (*Document, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return newDocument(b, filepath.Clean(name))
+}
+
+// Bundle returns the given document as a single YAML document i.e., with its references to other
+// files resolved; relative references are resolved from the working directory.
+func Bundle(b []byte) ([]byte, error) {
+	return bundle(b, "")
+}
+
+// BundleFile returns the given file's document as a single YAML document i.e., with its references
+// to other files resolved; relative references are resolved from the file's directory.
+func BundleFile(name string) ([]byte, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return bundle(b, filepath.Clean(name))
+}
+
+// bundle returns the given content of the given file as a single YAML document.
+func bundle(b []byte, file string) ([]byte, error) {
+	doc, root, pc, err := preParse(b, file)
+	if err != nil {
+		return nil, err
+	}
+	if err = pc.err(); err != nil {
+		return nil, err
+	}
+	// The resolved sections take over the original ones; definitions and responses of other files
+	// have been copied over.
+	if doc.OpenAPI == "" {
+		root["definitions"] = doc.Definitions
+		root["responses"] = doc.Responses
+		if doc.Parameters != nil {
+			root["parameters"] = doc.Parameters
+		}
+	} else {
+		components, _ := root["components"].(Record)
+		if components == nil {
+			components = make(Record)
+		}
+		components["schemas"] = doc.Components.Schemas
+		components["responses"] = doc.Components.Responses
+		if doc.Components.Parameters != nil {
+			components["parameters"] = doc.Components.Parameters
+		}
+		if doc.Components.RequestBodies != nil {
+			components["requestBodies"] = doc.Components.RequestBodies
+		}
+		root["components"] = components
+	}
+	if doc.Paths != nil {
+		root["paths"] = doc.Paths
+	}
+	return yaml.Marshal(root)
+}
+
+// preParse decodes the given content of the given file, and resolves its references.
+func preParse(b []byte, file string) (*preParsedDocument, Record, *parseContext, error) {
 	var doc preParsedDocument
 	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, nil, err
+	}
+	root := make(Record)
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return nil, nil, nil, err
+	}
+	pc := newParseContext(b)
+	resolveRefs(pc, &doc, root, file)
+	return &doc, root, pc, nil
+}
+
+// newDocument returns a new instance of `Document` from the given content of the given file.
+func newDocument(b []byte, file string) (*Document, error) {
+	doc, _, pc, err := preParse(b, file)
+	if err != nil {
 		return nil, err
 	}
-	return &Document{
+	if doc.OpenAPI == "" {
+		result := &Document{
+			SpecVersion: doc.Swagger,
+			Meta:        doc.Meta,
+			BasePath:    doc.BasePath,
+			Host:        doc.Host,
+			Definitions: parseIntoDefinitions(pc, doc.Definitions, "#/definitions"),
+			Responses:   parseIntoResponses(pc, doc.Responses, "#/responses"),
+			Paths:       parseIntoPaths(pc, doc.Paths, doc.Consumes, doc.Produces),
+			Sources:     pc.sources,
+		}
+		if err := pc.err(); err != nil {
+			return nil, err
+		}
+		resolveResponses(result)
+		resolveEnums(result)
+		return result, nil
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("parser: unsupported openapi version '%s'", doc.OpenAPI)
+	}
+
+	components := doc.Components
+	if components == nil {
+		components = &preParsedComponents{}
+	}
+	host, basePath, err := parseServer(doc.Servers)
+	if err != nil {
+		return nil, err
+	}
+	result := &Document{
+		SpecVersion: doc.OpenAPI,
 		Meta:        doc.Meta,
-		BasePath:    doc.BasePath,
-		Host:        doc.Host,
-		Definitions: parseIntoDefinitions(doc.Definitions),
-		Responses:   parseIntoResponses(doc.Responses),
-		Paths:       parseIntoPaths(doc.Paths),
-	}, nil
+		BasePath:    basePath,
+		Host:        host,
+		Definitions: parseIntoDefinitions(pc, components.Schemas, "#/components/schemas"),
+		Responses:   parseIntoResponses(pc, components.Responses, "#/components/responses"),
+		Paths:       parseIntoPaths(pc, doc.Paths, nil, nil),
+		Sources:     pc.sources,
+	}
+	if err := pc.err(); err != nil {
+		return nil, err
+	}
+	resolveResponses(result)
+	resolveEnums(result)
+	return result, nil
+}
+
+// parseServer maps the first of the given servers into a host and base path.
+func parseServer(servers []*preParsedServer) (string, string, error) {
+	if len(servers) == 0 {
+		return "", "", nil
+	}
+	server := servers[0]
+
+	// Substitute server variables with their default value.
+	raw := server.URL
+	for k, v := range server.Variables {
+		if v != nil {
+			raw = strings.ReplaceAll(raw, "{"+k+"}", v.Default)
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("parser: invalid server url '%s': %w", server.URL, err)
+	}
+	return u.Host, strings.TrimSuffix(u.Path, "/"), nil
 }
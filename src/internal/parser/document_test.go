@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		// The expected problems, as "{pointer} (line {line}): {message}".
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member:
+    type: object
+    properties:
+      name: {type: string, maxLength: 20}
+`,
+		},
+		{
+			name: "malformed keywords",
+			spec: `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Member:
+    type: object
+    properties:
+      name: {type: string, maxLength: long}
+      age: {type: integer, maximum: [1]}
+`,
+			want: []string{
+				`#/definitions/Member/properties/name/maxLength (line 9): expected an integer, got string "long"`,
+				`#/definitions/Member/properties/age/maximum (line 10): expected a number, got an array`,
+			},
+		},
+		{
+			name: "malformed property",
+			spec: `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Member:
+      type: object
+      required: [name]
+      properties:
+        name: string
+`,
+			want: []string{
+				`#/components/schemas/Member/properties/name (line 11): expected an object, got string "string"`,
+			},
+		},
+		{
+			name: "malformed enumeration",
+			spec: `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Member:
+      type: object
+      properties:
+        role: {type: string, enum: [admin, {name: user}]}
+`,
+			want: []string{
+				`#/components/schemas/Member/properties/role/enum/1 (line 10): expected a scalar, got an object`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDocument([]byte(tt.spec))
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("NewDocument() error = %v, want none", err)
+				}
+				return
+			}
+			var errs ParseErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("NewDocument() error = %v, want ParseErrors", err)
+			}
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				got = append(got, formatProblem(e))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewDocument() problems = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("NewDocument() problem %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// formatProblem returns the given problem as "{pointer} (line {line}): {message}".
+func formatProblem(e *ParseError) string {
+	return fmt.Sprintf("%s (line %d): %s", e.Pointer, e.Line, e.Message)
+}
+
+func TestParseErrorsOrder(t *testing.T) {
+	pc := &parseContext{
+		origins: map[string]string{
+			"#/definitions/Address": "common.yaml#/Address",
+			"common.yaml#/Address":  "common.yaml#/Address",
+		},
+		positions: map[string]position{
+			"#/definitions/Member/properties/age":  {Line: 12, Column: 7},
+			"#/definitions/Member/properties/name": {Line: 9, Column: 7},
+			"common.yaml#/Address/properties/city": {Line: 4, Column: 5},
+			"common.yaml#/Address/properties/zip":  {Line: 2, Column: 5},
+		},
+	}
+	for _, ptr := range []string{
+		"#/definitions/Address/properties/city",
+		"#/definitions/Member/properties/age",
+		"#/definitions/Member/properties/unknown",
+		"#/definitions/Address/properties/zip",
+		"#/definitions/Member/properties/name",
+	} {
+		pc.errorf(ptr, "invalid")
+	}
+	var errs ParseErrors
+	if !errors.As(pc.err(), &errs) {
+		t.Fatal("err() = nil, want ParseErrors")
+	}
+	// Problems are sorted by file, the main document's first, and those of unknown position last.
+	want := []string{
+		"#/definitions/Member/properties/name (line 9): invalid",
+		"#/definitions/Member/properties/age (line 12): invalid",
+		"#/definitions/Member/properties/unknown (line 0): invalid",
+		"common.yaml#/Address/properties/zip (line 2): invalid",
+		"common.yaml#/Address/properties/city (line 4): invalid",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("err() problems = %v, want %q", errs, want)
+	}
+	for i, e := range errs {
+		if got := formatProblem(e); got != want[i] {
+			t.Errorf("err() problem %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestNewDocumentOpenAPI3(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+openapi: 3.0.3
+info: {title: t, version: "1"}
+servers:
+  - url: https://{env}.example.com/v1
+    variables:
+      env: {default: api}
+paths:
+  /members:
+    post:
+      operationId: createMember
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/CreateMemberRequestBody'}
+      responses:
+        '200': {$ref: '#/components/responses/MemberResponse'}
+components:
+  schemas:
+    Member:
+      type: object
+      properties:
+        id: {type: string}
+    CreateMemberRequestBody:
+      type: object
+      properties:
+        name: {type: string}
+  responses:
+    MemberResponse:
+      description: ok
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Member'}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SpecVersion != "3.0.3" || doc.Host != "api.example.com" || doc.BasePath != "/v1" {
+		t.Errorf("NewDocument() version, host, base path = %q, %q, %q; want the server's", doc.SpecVersion, doc.Host, doc.BasePath)
+	}
+	for _, k := range []string{"Member", "CreateMemberRequestBody"} {
+		if _, ok := doc.Definitions[k]; !ok {
+			t.Errorf("NewDocument() lacks the component schema '%s'", k)
+		}
+	}
+	if _, ok := doc.Responses["MemberResponse"]; !ok {
+		t.Error("NewDocument() lacks the component response 'MemberResponse'")
+	}
+	path, ok := doc.Paths["POST /members"]
+	if !ok {
+		t.Fatal("NewDocument() lacks the operation 'POST /members'")
+	}
+	// The request body is mapped onto a body parameter.
+	if len(path.Parameters) != 1 {
+		t.Fatalf("operation parameters = %d, want the request body's", len(path.Parameters))
+	}
+	if body := path.Parameters[0]; body.In != "body" || body.Ref != "CreateMemberRequestBody" || !body.Required {
+		t.Errorf("request body = %+v, want a required body parameter referencing CreateMemberRequestBody", body)
+	}
+	if len(path.Consumes) != 1 || path.Consumes[0] != "application/json" {
+		t.Errorf("operation consumes %q, want application/json", path.Consumes)
+	}
+}
+
+func TestNewDocumentUnsupportedVersion(t *testing.T) {
+	if _, err := NewDocument([]byte("openapi: 4.0.0\ninfo: {title: t, version: \"1\"}\npaths: {}\n")); err == nil {
+		t.Fatal("NewDocument() error = nil, want an unsupported version error")
+	}
+}
@@ -0,0 +1,335 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError represents a problem found within a document.
+type ParseError struct {
+	// The JSON pointer to the faulty value e.g., "#/definitions/Member/properties/age/maximum".
+	Pointer string
+	// The faulty value's line within the document, if known.
+	Line int
+	// The faulty value's column within the document, if known.
+	Column int
+	// The problem's description.
+	Message string
+}
+
+// Error implements the `error` interface.
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.Pointer + ": " + e.Message
+	}
+	return fmt.Sprintf("%s (line %d, column %d): %s", e.Pointer, e.Line, e.Column, e.Message)
+}
+
+// ParseErrors represents every problem found within a document, in order of appearance.
+type ParseErrors []*ParseError
+
+// Error implements the `error` interface.
+func (e ParseErrors) Error() string {
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("parser: %d problem(s) found", len(e)))
+	for _, err := range e {
+		lines = append(lines, "\t"+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// position represents the position of a value within a document.
+type position struct {
+	Line   int
+	Column int
+	// The value's index within its parent mapping or sequence i.e., its declaration order.
+	Index int
+}
+
+// parseContext represents the state shared while parsing a document: the position of each of its
+// values, the origin of the values copied over from elsewhere, the raw content of the files read,
+// and the problems found so far.
+type parseContext struct {
+	positions map[string]position
+	origins   map[string]string
+	sources   [][]byte
+	errs      ParseErrors
+}
+
+// newParseContext returns a new instance of `parseContext` for the given document.
+func newParseContext(b []byte) *parseContext {
+	pc := &parseContext{
+		positions: make(map[string]position),
+		origins:   make(map[string]string),
+		sources:   [][]byte{b},
+	}
+	// Positions are a nicety; documents which can't be indexed are reported without them.
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err == nil && len(root.Content) > 0 {
+		indexPositions(pc.positions, "#", 0, root.Content[0])
+	}
+	return pc
+}
+
+// indexPositions records the position of the given node, found at the given index of its parent,
+// and of its descendants, under their JSON pointer.
+func indexPositions(positions map[string]position, ptr string, index int, node *yaml.Node) {
+	positions[ptr] = position{Line: node.Line, Column: node.Column, Index: index}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			indexPositions(positions, pointerTo(ptr, node.Content[i].Value), i/2, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			indexPositions(positions, pointerTo(ptr, i), i, child)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			indexPositions(positions, ptr, index, node.Alias)
+		}
+	}
+}
+
+// indexOf returns the index of the value at the given JSON pointer within its parent mapping or
+// sequence i.e., its declaration order, and whether it's known.
+func (pc *parseContext) indexOf(ptr string) (int, bool) {
+	pos, ok := pc.positions[pc.origin(ptr)]
+	return pos.Index, ok
+}
+
+// pointerTo returns the JSON pointer to the given reference tokens, relative to the given pointer.
+func pointerTo(ptr string, tokens ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(ptr)
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(fmt.Sprint(token)))
+	}
+	return b.String()
+}
+
+// errorf records a problem with the value at the given JSON pointer.
+func (pc *parseContext) errorf(ptr, format string, args ...interface{}) {
+	ptr = pc.origin(ptr)
+	pos := pc.positions[ptr]
+	err := &ParseError{
+		Pointer: ptr,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: fmt.Sprintf(format, args...),
+	}
+	// Values shared by several operations, such as path-level parameters, are reported once.
+	for _, existing := range pc.errs {
+		if *existing == *err {
+			return
+		}
+	}
+	pc.errs = append(pc.errs, err)
+}
+
+// origin returns the original location of the value at the given JSON pointer, for values copied
+// over by the resolution of references e.g., "common.yaml#/definitions/Address/properties/city".
+func (pc *parseContext) origin(ptr string) string {
+	// Values may be copied over from one another in a loop, when their references form a cycle.
+	for i := 0; i <= len(pc.origins); i++ {
+		// The closest copied value, found by walking up the pointer, holds the original location.
+		prefix := ptr
+		for {
+			if _, ok := pc.origins[prefix]; ok {
+				break
+			}
+			i := strings.LastIndexByte(prefix, '/')
+			if i < 0 {
+				return ptr
+			}
+			prefix = prefix[:i]
+		}
+		if pc.origins[prefix] == prefix {
+			return ptr
+		}
+		ptr = pc.origins[prefix] + strings.TrimPrefix(ptr, prefix)
+	}
+	return ptr
+}
+
+// err returns the problems found so far, sorted by file, the main document's first, then by
+// position, or nil if there are none; problems whose position is unknown come last.
+func (pc *parseContext) err() error {
+	if len(pc.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(pc.errs, func(i, j int) bool {
+		a, b := pc.errs[i], pc.errs[j]
+		if fileA, fileB := sourceFile(a.Pointer), sourceFile(b.Pointer); fileA != fileB {
+			return fileA < fileB
+		}
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return pc.errs
+}
+
+// sourceFile returns the file the given JSON pointer's value is found within, relative to the main
+// document's directory e.g., "common.yaml"; empty for the main document, which sorts first.
+func sourceFile(ptr string) string {
+	if i := strings.IndexByte(ptr, '#'); i >= 0 {
+		return ptr[:i]
+	}
+	return ""
+}
+
+// unexpected records a value which isn't of the expected kind.
+func (pc *parseContext) unexpected(ptr, expected string, v interface{}) {
+	pc.errorf(ptr, "expected %s, got %s", expected, describeValue(v))
+}
+
+// stringAt returns the string under the given key of the given record; values of another kind are
+// recorded as problems.
+func (pc *parseContext) stringAt(rec Record, key, ptr string) string {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return ""
+	}
+	vTyped, ok := v.(string)
+	if !ok {
+		pc.unexpected(pointerTo(ptr, key), "a string", v)
+	}
+	return vTyped
+}
+
+// boolAt returns the boolean under the given key of the given record; values of another kind are
+// recorded as problems.
+func (pc *parseContext) boolAt(rec Record, key, ptr string) bool {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return false
+	}
+	vTyped, ok := v.(bool)
+	if !ok {
+		pc.unexpected(pointerTo(ptr, key), "a boolean", v)
+	}
+	return vTyped
+}
+
+// intAt returns the integer under the given key of the given record; values of another kind are
+// recorded as problems.
+func (pc *parseContext) intAt(rec Record, key, ptr string) int {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return 0
+	}
+	switch vTyped := v.(type) {
+	case int:
+		return vTyped
+	case float64:
+		// Integral numbers written with a fractional part e.g., "2.0".
+		if vTyped == float64(int(vTyped)) {
+			return int(vTyped)
+		}
+	}
+	pc.unexpected(pointerTo(ptr, key), "an integer", v)
+	return 0
+}
+
+// numberAt returns the number under the given key of the given record; values of another kind are
+// recorded as problems.
+func (pc *parseContext) numberAt(rec Record, key, ptr string) float64 {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return 0
+	}
+	switch vTyped := v.(type) {
+	case int:
+		return float64(vTyped)
+	case float64:
+		return vTyped
+	}
+	pc.unexpected(pointerTo(ptr, key), "a number", v)
+	return 0
+}
+
+// recordAt returns the record under the given key of the given record, and whether there is one;
+// values of another kind are recorded as problems.
+func (pc *parseContext) recordAt(rec Record, key, ptr string) (Record, bool) {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+	vTyped, ok := v.(Record)
+	if !ok {
+		pc.unexpected(pointerTo(ptr, key), "an object", v)
+	}
+	return vTyped, ok
+}
+
+// sliceAt returns the sequence under the given key of the given record, and whether there is one;
+// values of another kind are recorded as problems.
+func (pc *parseContext) sliceAt(rec Record, key, ptr string) ([]interface{}, bool) {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+	vTyped, ok := v.([]interface{})
+	if !ok {
+		pc.unexpected(pointerTo(ptr, key), "an array", v)
+	}
+	return vTyped, ok
+}
+
+// refAt returns the reference key under the given key of the given record.
+func (pc *parseContext) refAt(rec Record, key, ptr string) string {
+	if ref := pc.stringAt(rec, key, ptr); ref != "" {
+		return toRef(ref)
+	}
+	return ""
+}
+
+// typeAt returns the schema type under the given key of the given record (see `parseType`); values
+// of another kind are recorded as problems.
+func (pc *parseContext) typeAt(rec Record, key, ptr string) (string, []string, bool) {
+	v, ok := rec[key]
+	if !ok || v == nil {
+		return "", nil, false
+	}
+	switch vTyped := v.(type) {
+	case string:
+	case []interface{}:
+		for i, entry := range vTyped {
+			if _, ok := entry.(string); !ok {
+				pc.unexpected(pointerTo(ptr, key, i), "a string", entry)
+			}
+		}
+	default:
+		pc.unexpected(pointerTo(ptr, key), "a string or an array", v)
+	}
+	return parseType(v)
+}
+
+// describeValue describes the kind of the given value, for use within problem descriptions.
+func describeValue(v interface{}) string {
+	switch vTyped := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string " + strconv.Quote(vTyped)
+	case Record:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	default:
+		if t := typeOfValue(v); t != "" {
+			return fmt.Sprintf("%s %v", t, v)
+		}
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Extensions represents the specification extensions of an object i.e., its "x-" prefixed keys,
+// by name.
+type Extensions map[string]interface{}
+
+// isExtension checks whether the given key is a specification extension.
+func isExtension(key interface{}) bool {
+	s, ok := key.(string)
+	return ok && strings.HasPrefix(s, "x-")
+}
+
+// parseExtensions maps the specification extensions of the given object into a new instance of
+// `Extensions`, or nil if it has none.
+func parseExtensions(rec Record) Extensions {
+	var ext Extensions
+	for k, v := range rec {
+		if !isExtension(k) {
+			continue
+		}
+		if ext == nil {
+			ext = make(Extensions)
+		}
+		ext[k.(string)] = normalizeValue(v)
+	}
+	return ext
+}
+
+// withPrefix returns the extensions whose name starts with the given prefix, or nil if there are
+// none.
+func (e Extensions) withPrefix(prefix string) Extensions {
+	var result Extensions
+	for k, v := range e {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if result == nil {
+			result = make(Extensions)
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// String returns the string value of the given extension, or an empty string if there is none.
+func (e Extensions) String(name string) string {
+	switch v := e[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Bool returns the boolean value of the given extension, or false if there is none.
+func (e Extensions) Bool(name string) bool {
+	v, _ := e[name].(bool)
+	return v
+}
+
+// Strings returns the string values of the given extension, or nil if there are none.
+func (e Extensions) Strings(name string) []string {
+	v, ok := e[name].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(v))
+	for _, entry := range v {
+		result = append(result, fmt.Sprint(entry))
+	}
+	return result
+}
@@ -1,107 +1,302 @@
 package parser
 
-// Path represents an API path.
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Path represents an API operation i.e., a path and HTTP verb pair.
 type Path struct {
 	Key         string
 	Description string
 	HTTPVerb    string
 	Parameters  []*DefinitionProperty
 	Operation   string
+	// The operation's specification extensions.
+	Extensions Extensions
+	// The operation's responses, keyed by status code.
+	Responses map[string]*PathResponse
+	// The operation's request media types.
+	Consumes []string
+	// The operation's response media types.
+	Produces []string
+	// The operation's rank within the document, in declaration order.
+	Order int
+}
+
+// PathMapKey returns the key under which the operation for the given verb and path is stored in
+// `Document.Paths` e.g., "GET /members".
+func PathMapKey(verb, key string) string {
+	return strings.ToUpper(verb) + " " + key
 }
 
-// parseIntoPaths maps swagger definitions into a new instance of `map[string]*Path`,
-func parseIntoPaths(rawDefs map[string]interface{}) map[string]*Path {
+// httpVerbs are the keys of a path item which describe an operation; the others, such as
+// "parameters", apply to every operation of the path.
+var httpVerbs = map[string]bool{
+	"get":     true,
+	"put":     true,
+	"post":    true,
+	"delete":  true,
+	"options": true,
+	"head":    true,
+	"patch":   true,
+	"trace":   true,
+}
+
+// parseIntoPaths maps swagger definitions into a new instance of `map[string]*Path`, holding one
+// entry per operation; operations inherit the given document-wide media types, and the parameters
+// of their path.
+func parseIntoPaths(pc *parseContext, rawDefs map[string]interface{}, consumes, produces []string) map[string]*Path {
 	pathMap := make(map[string]*Path)
+	// The operations' declaration order i.e., that of their path then that of their verb.
+	declared := make(map[*Path][2]int)
 	for k, v := range rawDefs {
-		path := &Path{
-			Key: k,
+		pathPtr := pointerTo("#/paths", k)
+		vTyped, ok := v.(Record)
+		if !ok {
+			pc.unexpected(pathPtr, "an object", v)
+			continue
 		}
-		if vTyped, ok := v.(Record); ok {
-			for verbKey, verbVal := range vTyped {
-				path.HTTPVerb = verbKey.(string)
-				if verbValTyped, ok := verbVal.(Record); ok {
-					if desc := verbValTyped["summary"]; desc != nil {
-						path.Description = desc.(string)
-					}
-					if parameters := verbValTyped["parameters"]; parameters != nil {
-						if parametersTyped, ok := parameters.([]interface{}); ok {
-							params := make([]*DefinitionProperty, 0)
-							for _, paramVal := range parametersTyped {
-								param := &DefinitionProperty{
-									Validation: &DefinitionPropertyValidation{},
-								}
-								if paramValTyped, ok := paramVal.(Record); ok {
-									// Properties.
-									if paramName := paramValTyped["name"]; paramName != nil {
-										param.Key = paramName.(string)
-									}
-									if paramDesc := paramValTyped["description"]; paramDesc != nil {
-										param.Description = extractDescription(paramDesc.(string))
-									}
-									if paramIn := paramValTyped["in"]; paramIn != nil {
-										param.In = paramIn.(string)
-									}
-									if paramRequired := paramValTyped["required"]; paramRequired != nil {
-										param.Required = paramRequired.(bool)
-									}
-									if paramType := paramValTyped["type"]; paramType != nil {
-										param.Type = paramType.(string)
-									}
-									if paramFormat := paramValTyped["format"]; paramFormat != nil {
-										param.Format = paramFormat.(string)
-									}
-									if paramRef := paramValTyped["$ref"]; paramRef != nil {
-										param.Ref = toRef(paramRef.(string))
-									}
-									if paramItemsRef := paramValTyped["items"]; paramItemsRef != nil {
-										if paramItemsRefTyped, ok := paramItemsRef.(Record); ok {
-											if paramSliceRef := paramItemsRefTyped["type"]; paramSliceRef != nil {
-												param.Ref = paramSliceRef.(string)
-											}
-											if paramSliceRef := paramItemsRefTyped["$ref"]; paramSliceRef != nil {
-												param.Ref = toRef(paramSliceRef.(string))
-											}
-										}
-									}
-									if paramSchema := paramValTyped["schema"]; paramSchema != nil {
-										if paramSchemaTyped, ok := paramSchema.(Record); ok {
-											if paramRef := paramSchemaTyped["$ref"]; paramRef != nil {
-												param.Ref = toRef(paramRef.(string))
-											}
-											if paramType := paramSchemaTyped["type"]; paramType != nil {
-												param.Type = paramType.(string)
-											}
-										}
-									}
-									// Validation properties.
-									if paramPattern := paramValTyped["pattern"]; paramPattern != nil {
-										param.Validation.Pattern = paramPattern.(string)
-									}
-									if paramMinLength := paramValTyped["minLength"]; paramMinLength != nil {
-										param.Validation.MinLength = paramMinLength.(int)
-									}
-									if paramMaxLength := paramValTyped["maxLength"]; paramMaxLength != nil {
-										param.Validation.MaxLength = paramMaxLength.(int)
-									}
-									if paramMinItems := paramValTyped["minItems"]; paramMinItems != nil {
-										param.Validation.MinItems = paramMinItems.(int)
-									}
-									if paramMaxItems := paramValTyped["maxItems"]; paramMaxItems != nil {
-										param.Validation.MaxItems = paramMaxItems.(int)
-									}
-								}
-								params = append(params, param)
-							}
-							path.Parameters = params
-						}
-					}
-					if operationID := verbValTyped["operationId"]; operationID != nil {
-						path.Operation = operationID.(string)
-					}
+		for _, verbKey := range sortedRecordKeys(vTyped) {
+			if !httpVerbs[fmt.Sprint(verbKey)] {
+				continue
+			}
+			path := &Path{
+				Key:      k,
+				HTTPVerb: fmt.Sprint(verbKey),
+				Consumes: consumes,
+				Produces: produces,
+			}
+			opPtr := pointerTo(pathPtr, path.HTTPVerb)
+			// Browsers refuse to send TRACE requests, which the generated clients would fail with.
+			if path.HTTPVerb == "trace" {
+				pc.errorf(opPtr, "unsupported method 'trace'")
+				continue
+			}
+			verbValTyped, ok := vTyped[verbKey].(Record)
+			if !ok {
+				pc.unexpected(opPtr, "an object", vTyped[verbKey])
+				continue
+			}
+			path.Description = pc.stringAt(verbValTyped, "summary", opPtr)
+			path.Extensions = parseExtensions(verbValTyped)
+			// Path-level parameters are parsed for each operation, as operations may override them.
+			path.Parameters = mergeParameters(
+				parseParameters(pc, vTyped, pathPtr),
+				parseParameters(pc, verbValTyped, opPtr),
+			)
+			// OpenAPI 3 request bodies are mapped onto a Swagger 2 body parameter.
+			if requestBody, ok := pc.recordAt(verbValTyped, "requestBody", opPtr); ok {
+				path.Parameters = append(path.Parameters, parseRequestBody(pc, requestBody, pointerTo(opPtr, "requestBody")))
+				path.Consumes = mediaTypes(requestBody["content"])
+			}
+			path.Operation = pc.stringAt(verbValTyped, "operationId", opPtr)
+			if responses, ok := pc.recordAt(verbValTyped, "responses", opPtr); ok {
+				path.Responses = parseIntoPathResponses(pc, responses, pointerTo(opPtr, "responses"))
+				// OpenAPI 3 responses describe their media types through their content.
+				if mediaTypes := responsesMediaTypes(responses); len(mediaTypes) > 0 {
+					path.Produces = mediaTypes
 				}
 			}
+			if opConsumes := verbValTyped["consumes"]; opConsumes != nil {
+				path.Consumes = toStringSlice(opConsumes)
+			}
+			if opProduces := verbValTyped["produces"]; opProduces != nil {
+				path.Produces = toStringSlice(opProduces)
+			}
+			// Operations without an identifier are named after their verb and path.
+			if path.Operation == "" {
+				path.Operation = toOperationID(path.HTTPVerb, path.Key)
+			}
+			pathMap[PathMapKey(path.HTTPVerb, path.Key)] = path
+			pathIndex, _ := pc.indexOf(pathPtr)
+			verbIndex, _ := pc.indexOf(opPtr)
+			declared[path] = [2]int{pathIndex, verbIndex}
 		}
-		pathMap[path.Key] = path
 	}
+	rankPaths(pathMap, declared)
 	return pathMap
 }
+
+// rankPaths sets the rank of the given operations as per their given declaration order; ties,
+// such as operations whose position is unknown, are broken by key.
+func rankPaths(pathMap map[string]*Path, declared map[*Path][2]int) {
+	keys := sortedPathKeys(pathMap)
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := declared[pathMap[keys[i]]], declared[pathMap[keys[j]]]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return a[1] < b[1]
+	})
+	for i, k := range keys {
+		pathMap[k].Order = i
+	}
+}
+
+// parseParameters maps the parameters of the given path item or operation, found under the given
+// JSON pointer, into a new slice of `DefinitionProperty`.
+func parseParameters(pc *parseContext, rec Record, ptr string) []*DefinitionProperty {
+	parameters, ok := pc.sliceAt(rec, "parameters", ptr)
+	if !ok {
+		return nil
+	}
+	params := make([]*DefinitionProperty, 0, len(parameters))
+	for i, paramVal := range parameters {
+		params = append(params, parseParameter(pc, paramVal, pointerTo(ptr, "parameters", i)))
+	}
+	return params
+}
+
+// mergeParameters returns the given path-level parameters, alongside the given operation-level
+// parameters; the latter override the former when they share a name and destination.
+func mergeParameters(pathParams, opParams []*DefinitionProperty) []*DefinitionProperty {
+	if len(pathParams) == 0 {
+		return opParams
+	}
+	overridden := make(map[string]bool, len(opParams))
+	for _, param := range opParams {
+		overridden[param.In+" "+param.Key] = true
+	}
+	result := make([]*DefinitionProperty, 0, len(pathParams)+len(opParams))
+	for _, param := range pathParams {
+		if !overridden[param.In+" "+param.Key] {
+			result = append(result, param)
+		}
+	}
+	return append(result, opParams...)
+}
+
+// parseParameter maps an operation's parameter, found under the given JSON pointer, into a new
+// instance of `DefinitionProperty`.
+func parseParameter(pc *parseContext, v interface{}, ptr string) *DefinitionProperty {
+	param := &DefinitionProperty{
+		Validation: &DefinitionPropertyValidation{},
+	}
+	paramValTyped, ok := v.(Record)
+	if !ok {
+		pc.unexpected(ptr, "an object", v)
+		return param
+	}
+	// Properties.
+	param.Key = pc.stringAt(paramValTyped, "name", ptr)
+	param.Description = extractDescription(pc.stringAt(paramValTyped, "description", ptr))
+	param.In = pc.stringAt(paramValTyped, "in", ptr)
+	param.Required = pc.boolAt(paramValTyped, "required", ptr)
+	param.Type, param.Union, param.Nullable = pc.typeAt(paramValTyped, "type", ptr)
+	param.Format = pc.stringAt(paramValTyped, "format", ptr)
+	param.Ref = pc.refAt(paramValTyped, "$ref", ptr)
+	param.CollectionFormat = pc.stringAt(paramValTyped, "collectionFormat", ptr)
+	param.Extensions = parseExtensions(paramValTyped)
+	if paramItems, ok := pc.recordAt(paramValTyped, "items", ptr); ok {
+		itemsPtr := pointerTo(ptr, "items")
+		if itemsType, _, _ := pc.typeAt(paramItems, "type", itemsPtr); itemsType != "" {
+			param.Ref = itemsType
+		}
+		if itemsRef := pc.refAt(paramItems, "$ref", itemsPtr); itemsRef != "" {
+			param.Ref = itemsRef
+		}
+	}
+	// OpenAPI 3 parameters describe their type through a schema.
+	if paramSchema, ok := pc.recordAt(paramValTyped, "schema", ptr); ok {
+		schemaPtr := pointerTo(ptr, "schema")
+		if schemaRef := pc.refAt(paramSchema, "$ref", schemaPtr); schemaRef != "" {
+			param.Ref = schemaRef
+		}
+		if _, ok := paramSchema["type"]; ok {
+			var nullable bool
+			param.Type, param.Union, nullable = pc.typeAt(paramSchema, "type", schemaPtr)
+			param.Nullable = param.Nullable || nullable
+		}
+		if schemaFormat := pc.stringAt(paramSchema, "format", schemaPtr); schemaFormat != "" {
+			param.Format = schemaFormat
+		}
+		if schemaItems, ok := pc.recordAt(paramSchema, "items", schemaPtr); ok {
+			itemsPtr := pointerTo(schemaPtr, "items")
+			if itemsType, _, _ := pc.typeAt(schemaItems, "type", itemsPtr); itemsType != "" {
+				param.Ref = itemsType
+			}
+			if itemsRef := pc.refAt(schemaItems, "$ref", itemsPtr); itemsRef != "" {
+				param.Ref = itemsRef
+			}
+		}
+		// OpenAPI 3 array parameters describe their serialization through a style.
+		if param.In == "query" && param.Type == "array" && param.CollectionFormat == "" {
+			param.CollectionFormat = toCollectionFormat(paramValTyped["style"], paramValTyped["explode"])
+		}
+	}
+	// Validation properties.
+	param.Validation = parseValidation(pc, paramValTyped, ptr)
+	return param
+}
+
+// toOperationID returns an operation identifier derived from the given verb and path e.g.,
+// "get" and "/members/{member_id}" produce "getMembersMemberId".
+func toOperationID(verb, key string) string {
+	return strcase.ToLowerCamel(strings.ToLower(verb) + " " + strings.NewReplacer("/", " ", "{", " ", "}", " ").Replace(key))
+}
+
+// parseRequestBody maps an OpenAPI 3 request body, found under the given JSON pointer, into a
+// body parameter.
+func parseRequestBody(pc *parseContext, requestBody Record, ptr string) *DefinitionProperty {
+	param := &DefinitionProperty{
+		Key:        "Body",
+		In:         "body",
+		Validation: &DefinitionPropertyValidation{},
+	}
+	param.Description = extractDescription(pc.stringAt(requestBody, "description", ptr))
+	param.Required = pc.boolAt(requestBody, "required", ptr)
+	param.Extensions = parseExtensions(requestBody)
+	if schema, schemaPtr := mediaTypeSchema(requestBody["content"], pointerTo(ptr, "content")); schema != nil {
+		param.Ref = pc.refAt(schema, "$ref", schemaPtr)
+		param.Type, param.Union, param.Nullable = pc.typeAt(schema, "type", schemaPtr)
+	}
+	return param
+}
+
+// toCollectionFormat maps an OpenAPI 3 parameter style into its Swagger 2 collection format
+// equivalent.
+func toCollectionFormat(style, explode interface{}) string {
+	styleTyped, _ := style.(string)
+	explodeTyped, ok := explode.(bool)
+	if !ok {
+		// Only the "form" style explodes by default.
+		explodeTyped = styleTyped == "" || styleTyped == "form"
+	}
+	switch styleTyped {
+	case "spaceDelimited":
+		return "ssv"
+	case "pipeDelimited":
+		return "pipes"
+	default:
+		if explodeTyped {
+			return "multi"
+		}
+		return "csv"
+	}
+}
+
+// responsesMediaTypes returns the media types of an operation's success responses (OpenAPI 3
+// only).
+func responsesMediaTypes(rawResps Record) []string {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+	for k, v := range rawResps {
+		if code := fmt.Sprint(k); !strings.HasPrefix(code, "2") && code != "default" {
+			continue
+		}
+		if vTyped, ok := v.(Record); ok {
+			for _, mediaType := range mediaTypes(vTyped["content"]) {
+				if !seen[mediaType] {
+					seen[mediaType] = true
+					result = append(result, mediaType)
+				}
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPathOperations(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members:
+    get:
+      operationId: listMembers
+      responses:
+        '200': {description: ok}
+    post:
+      operationId: createMember
+      responses:
+        '200': {description: ok}
+  /members/{member_id}:
+    delete:
+      responses:
+        '204': {description: no content}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"GET /members":                "listMembers",
+		"POST /members":               "createMember",
+		"DELETE /members/{member_id}": "deleteMembersMemberId",
+	}
+	if len(doc.Paths) != len(want) {
+		t.Errorf("NewDocument() operations = %d, want %d", len(doc.Paths), len(want))
+	}
+	for k, operation := range want {
+		path, ok := doc.Paths[k]
+		if !ok {
+			t.Errorf("NewDocument() lacks the operation '%s'", k)
+			continue
+		}
+		if path.Operation != operation {
+			t.Errorf("operation '%s' identifier = %q, want %q", k, path.Operation, operation)
+		}
+	}
+}
+
+func TestPathResponses(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members/{member_id}:
+    get:
+      operationId: getMember
+      responses:
+        '404': {description: not found, schema: {$ref: '#/definitions/Error'}}
+        '201': {description: created, schema: {$ref: '#/definitions/GetMemberBody'}}
+        '200': {description: ok, schema: {$ref: '#/definitions/GetMemberBody'}}
+    delete:
+      operationId: deleteMember
+      responses:
+        default: {description: error, schema: {$ref: '#/definitions/Error'}}
+        '400': {description: bad request}
+definitions:
+  Member:
+    type: object
+  Error:
+    type: object
+  GetMemberBody:
+    type: object
+    properties:
+      data: {$ref: '#/definitions/Member'}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := doc.Paths["GET /members/{member_id}"]
+	if len(get.Responses) != 3 {
+		t.Errorf("getMember responses = %d, want 3", len(get.Responses))
+	}
+	// The lowest 2xx response is the success one, registered as a response of its own.
+	resp := get.SuccessResponse()
+	if resp == nil || resp.Code != "200" || resp.Ref != "GetMemberResponse" {
+		t.Fatalf("getMember success response = %+v, want 200 registered as GetMemberResponse", resp)
+	}
+	if got := doc.Responses["GetMemberResponse"]; got == nil || got.Ref != "GetMemberBody" || got.Returns != "Member" {
+		t.Errorf("GetMemberResponse = %+v, want GetMemberBody returning Member", got)
+	}
+	// The default response of operations declaring errors describes errors.
+	if resp := doc.Paths["DELETE /members/{member_id}"].SuccessResponse(); resp != nil {
+		t.Errorf("deleteMember success response = %+v, want none", resp)
+	}
+}
+
+func TestPathLevelParameters(t *testing.T) {
+	doc, err := NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+parameters:
+  OrgID: {in: path, name: organisation_id, type: string, required: true}
+paths:
+  /organisations/{organisation_id}/members:
+    parameters:
+      - $ref: '#/parameters/OrgID'
+      - {in: header, name: X-Trace, type: string}
+    get:
+      operationId: listOrgMembers
+      parameters:
+        - {in: query, name: limit, type: integer}
+      responses:
+        '204': {description: ok}
+    delete:
+      operationId: clearOrgMembers
+      parameters:
+        - {in: header, name: X-Trace, type: string, required: true}
+      responses:
+        '204': {description: ok}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key string
+		// The expected parameters, as "{in} {name}", and whether they're required.
+		want map[string]bool
+	}{
+		{
+			key:  "GET /organisations/{organisation_id}/members",
+			want: map[string]bool{"path organisation_id": true, "header X-Trace": false, "query limit": false},
+		},
+		{
+			// Operation-level parameters override path-level ones.
+			key:  "DELETE /organisations/{organisation_id}/members",
+			want: map[string]bool{"path organisation_id": true, "header X-Trace": true},
+		},
+	}
+	for _, tt := range tests {
+		params := doc.Paths[tt.key].Parameters
+		if len(params) != len(tt.want) {
+			t.Errorf("'%s' parameters = %d, want %d", tt.key, len(params), len(tt.want))
+		}
+		for _, param := range params {
+			if required, ok := tt.want[param.In+" "+param.Key]; !ok || required != param.Required {
+				t.Errorf("'%s' parameter '%s %s' required = %t, want %t (declared: %t)", tt.key, param.In, param.Key, param.Required, required, ok)
+			}
+		}
+	}
+}
+
+func TestTraceOperationsRejected(t *testing.T) {
+	_, err := NewDocument([]byte(`
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members:
+    trace:
+      responses:
+        '200': {description: ok}
+`))
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Pointer != "#/paths/~1members/trace" {
+		t.Fatalf("NewDocument() error = %v, want the trace operation reported", err)
+	}
+}
@@ -0,0 +1,558 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// refKind represents what a reference points to, which decides how it is resolved.
+type refKind int
+
+const (
+	// refKindSchema references are kept as references to definitions.
+	refKindSchema refKind = iota
+	// refKindResponse references are kept as references to responses.
+	refKindResponse
+	// refKindInline references are replaced by the value they point to e.g., parameters.
+	refKindInline
+)
+
+// resolver resolves the references of a document, which may be spread across files, so that every
+// remaining reference points to a definition or a response of the main document:
+//   - definitions and responses of other files are copied into the main document
+//   - references to anything else, such as parameters, are replaced by their target
+type resolver struct {
+	pc  *parseContext
+	doc *preParsedDocument
+	// The main document's file, if known, and raw content.
+	file string
+	root Record
+	// The directory from which the main document's relative references are resolved.
+	dir string
+	// The files loaded so far, by path.
+	files map[string]Record
+	// The local references given to the definitions and responses of other files, by location,
+	// and whether they have been copied into the main document yet.
+	registered map[string]string
+	copied     map[string]bool
+	// The locations of the references being replaced, in order, to detect cycles, and those of the
+	// cycles reported so far.
+	inlining []string
+	cycles   map[string]bool
+}
+
+// resolveRefs resolves the references of the given document; relative references are resolved
+// from the directory of the given file, or from the working directory if there is none.
+func resolveRefs(pc *parseContext, doc *preParsedDocument, root Record, file string) {
+	r := &resolver{
+		pc:         pc,
+		doc:        doc,
+		file:       file,
+		root:       root,
+		dir:        ".",
+		files:      make(map[string]Record),
+		registered: make(map[string]string),
+		copied:     make(map[string]bool),
+		cycles:     make(map[string]bool),
+	}
+	if file != "" {
+		r.dir = filepath.Dir(file)
+	}
+
+	if doc.OpenAPI == "" {
+		if doc.Definitions == nil {
+			doc.Definitions = make(map[string]interface{})
+		}
+		if doc.Responses == nil {
+			doc.Responses = make(map[string]interface{})
+		}
+		r.walkSchemas(doc.Definitions, "", "#/definitions")
+		r.walkResponses(doc.Responses, "", "#/responses")
+	} else {
+		if doc.Components == nil {
+			doc.Components = &preParsedComponents{}
+		}
+		if doc.Components.Schemas == nil {
+			doc.Components.Schemas = make(map[string]interface{})
+		}
+		if doc.Components.Responses == nil {
+			doc.Components.Responses = make(map[string]interface{})
+		}
+		r.walkSchemas(doc.Components.Schemas, "", "#/components/schemas")
+		r.walkResponses(doc.Components.Responses, "", "#/components/responses")
+	}
+	for _, k := range sortedMapKeys(doc.Paths) {
+		doc.Paths[k] = r.walkPath(doc.Paths[k], "", pointerTo("#/paths", k))
+	}
+	// Reusable parameters and request bodies are inlined where they're used; their own references
+	// are resolved nonetheless, so that they're bundled as such.
+	if doc.OpenAPI == "" {
+		r.walkInlines(doc.Parameters, "#/parameters")
+	} else {
+		r.walkInlines(doc.Components.Parameters, "#/components/parameters")
+		r.walkInlines(doc.Components.RequestBodies, "#/components/requestBodies")
+	}
+	r.checkAliasCycles()
+}
+
+// walkSchemas resolves the references of the given definitions, found under the given JSON
+// pointer of the given file.
+func (r *resolver) walkSchemas(defs map[string]interface{}, file, ptr string) {
+	// Definitions merely referencing another file's definition take over its content, so that the
+	// latter is registered under their name.
+	for _, k := range sortedMapKeys(defs) {
+		def, ok := defs[k].(Record)
+		if !ok || len(def) != 1 {
+			continue
+		}
+		ref, ok := def["$ref"].(string)
+		if !ok {
+			continue
+		}
+		if targetFile, fragment := r.splitRef(ref, file); targetFile != "" {
+			location := r.location(targetFile, "#"+fragment)
+			if _, ok := r.registered[location]; !ok {
+				_, prefix := r.section(refKindSchema)
+				r.registered[location] = prefix + k
+			}
+		}
+	}
+	for _, k := range sortedMapKeys(defs) {
+		resolved := r.walk(defs[k], file, pointerTo(ptr, k), refKindSchema)
+		// Definitions which took over another's content have been replaced already.
+		if resolvedTyped, ok := resolved.(Record); ok && len(resolvedTyped) == 1 && resolvedTyped["$ref"] == pointerTo(ptr, k) {
+			continue
+		}
+		defs[k] = resolved
+	}
+}
+
+// walkResponses resolves the references of the given responses, found under the given JSON
+// pointer of the given file.
+func (r *resolver) walkResponses(resps map[string]interface{}, file, ptr string) {
+	for _, k := range sortedMapKeys(resps) {
+		resps[k] = r.walkResponse(resps[k], file, pointerTo(ptr, k))
+	}
+}
+
+// walkInlines resolves the references of the given reusable values of the main document e.g.,
+// parameters, found under the given JSON pointer.
+func (r *resolver) walkInlines(values map[string]interface{}, ptr string) {
+	for _, k := range sortedMapKeys(values) {
+		values[k] = r.walkInline(values[k], "", pointerTo(ptr, k))
+	}
+}
+
+// walkPath resolves the references of the given path item.
+func (r *resolver) walkPath(v interface{}, file, ptr string) interface{} {
+	v = r.walk(v, file, ptr, refKindInline)
+	vTyped, ok := v.(Record)
+	if !ok {
+		return v
+	}
+	for _, k := range sortedRecordKeys(vTyped) {
+		opPtr := pointerTo(ptr, k)
+		if k == "parameters" {
+			vTyped[k] = r.walkParameters(vTyped[k], file, opPtr)
+			continue
+		}
+		op, ok := vTyped[k].(Record)
+		if !ok {
+			continue
+		}
+		for _, opKey := range sortedRecordKeys(op) {
+			switch opKey {
+			case "parameters":
+				op[opKey] = r.walkParameters(op[opKey], file, pointerTo(opPtr, opKey))
+			case "requestBody":
+				// Request bodies are inlined, whereas their schemas remain references.
+				op[opKey] = r.walkInline(op[opKey], file, pointerTo(opPtr, opKey))
+			case "responses":
+				if resps, ok := op[opKey].(Record); ok {
+					for _, code := range sortedRecordKeys(resps) {
+						resps[code] = r.walkResponse(resps[code], file, pointerTo(opPtr, opKey, code))
+					}
+				}
+			}
+		}
+	}
+	return vTyped
+}
+
+// walkParameters resolves the references of the given list of parameters.
+func (r *resolver) walkParameters(v interface{}, file, ptr string) interface{} {
+	vTyped, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	for i := range vTyped {
+		// Parameters are inlined, whereas their schemas remain references.
+		vTyped[i] = r.walkInline(vTyped[i], file, pointerTo(ptr, i))
+	}
+	return vTyped
+}
+
+// walkInline replaces the given value by its target, if it is a reference, and resolves the
+// references nested within.
+func (r *resolver) walkInline(v interface{}, file, ptr string) interface{} {
+	v = r.walk(v, file, ptr, refKindInline)
+	if vTyped, ok := v.(Record); ok && vTyped["$ref"] == nil {
+		return r.walk(vTyped, file, ptr, refKindSchema)
+	}
+	return v
+}
+
+// walkResponse resolves the references of the given response.
+func (r *resolver) walkResponse(v interface{}, file, ptr string) interface{} {
+	v = r.walk(v, file, ptr, refKindResponse)
+	if vTyped, ok := v.(Record); ok && vTyped["$ref"] == nil {
+		return r.walk(vTyped, file, ptr, refKindSchema)
+	}
+	return v
+}
+
+// walk resolves the references of the given value, found under the given JSON pointer of the
+// given file, and returns the value with its references resolved; the given kind applies to the
+// value's own reference, whereas nested references are considered schema references.
+func (r *resolver) walk(v interface{}, file, ptr string, kind refKind) interface{} {
+	switch vTyped := v.(type) {
+	case Record:
+		if ref, ok := vTyped["$ref"].(string); ok {
+			return r.resolve(vTyped, ref, file, pointerTo(ptr, "$ref"), kind)
+		}
+		if kind != refKindSchema {
+			return vTyped
+		}
+		for _, k := range sortedRecordKeys(vTyped) {
+			switch {
+			// Example and extension values are arbitrary, and may hold "$ref" keys of their own.
+			case k == "example", k == "examples", k == "const", k == "default", k == "enum", isExtension(k):
+				continue
+			}
+			vTyped[k] = r.walk(vTyped[k], file, pointerTo(ptr, k), refKindSchema)
+		}
+		return vTyped
+	case []interface{}:
+		if kind != refKindSchema {
+			return vTyped
+		}
+		for i := range vTyped {
+			vTyped[i] = r.walk(vTyped[i], file, pointerTo(ptr, i), refKindSchema)
+		}
+		return vTyped
+	default:
+		return v
+	}
+}
+
+// resolve resolves the given reference, found under the given JSON pointer of the given file.
+func (r *resolver) resolve(v Record, ref, file, ptr string, kind refKind) interface{} {
+	targetFile, fragment := r.splitRef(ref, file)
+	// References to the main document's definitions and responses are kept as they are.
+	if targetFile == "" {
+		if local := r.localRef(fragment, kind); local != "" {
+			if _, ok := resolvePointer(r.root, fragment); !ok {
+				r.pc.errorf(r.location(file, ptr), "unresolved reference '%s'", ref)
+			}
+			return v
+		}
+	}
+
+	target, ok := r.load(targetFile, file, ptr)
+	if !ok {
+		return v
+	}
+	targetVal, ok := resolvePointer(target, fragment)
+	if !ok {
+		r.pc.errorf(r.location(file, ptr), "unresolved reference '%s'", ref)
+		return v
+	}
+	location := r.location(targetFile, "#"+fragment)
+
+	// Definitions and responses of other files are copied into the main document.
+	if targetFile != "" && kind != refKindInline {
+		section, prefix := r.section(kind)
+		local, ok := r.registered[location]
+		if ok && r.copied[location] {
+			return Record{"$ref": local}
+		}
+		// Definitions may have been given a name already, by a definition referencing them.
+		if !ok {
+			local = prefix + reserveKey(mapKeys(section), refName(targetFile, fragment))
+			r.registered[location] = local
+		}
+		name := strings.TrimPrefix(local, prefix)
+		r.pc.origins[local] = location
+
+		// Flag the target as copied before walking it, as it may reference itself.
+		r.copied[location] = true
+		if kind == refKindResponse {
+			section[name] = r.walkResponse(deepCopy(targetVal), targetFile, "#"+fragment)
+		} else {
+			section[name] = r.walk(deepCopy(targetVal), targetFile, "#"+fragment, refKindSchema)
+		}
+		return Record{"$ref": local}
+	}
+
+	// Anything else is replaced by its target.
+	for i, inlined := range r.inlining {
+		if inlined == location {
+			// Cycles are reported once, although they may be entered through any of their values.
+			if !r.cycles[location] {
+				for _, l := range r.inlining[i:] {
+					r.cycles[l] = true
+				}
+				r.pc.errorf(r.location(file, ptr), "reference cycle: %s -> %s", strings.Join(r.inlining[i:], " -> "), location)
+			}
+			return v
+		}
+	}
+	r.inlining = append(r.inlining, location)
+	defer func() {
+		r.inlining = r.inlining[:len(r.inlining)-1]
+	}()
+	result := r.walk(deepCopy(targetVal), targetFile, "#"+fragment, kind)
+	r.pc.origins[r.location(file, strings.TrimSuffix(ptr, "/$ref"))] = location
+	return result
+}
+
+// splitRef splits the given reference, found within the given file, into the file it points to
+// ("" being the main document) and a JSON pointer within that file.
+func (r *resolver) splitRef(ref, file string) (string, string) {
+	refFile, fragment := ref, ""
+	if idx := strings.Index(ref, "#"); idx >= 0 {
+		refFile, fragment = ref[:idx], ref[idx+1:]
+	}
+	if refFile == "" {
+		return file, fragment
+	}
+	dir := r.dir
+	if file != "" {
+		dir = filepath.Dir(file)
+	}
+	target := filepath.Join(dir, filepath.FromSlash(refFile))
+	if r.file != "" && filepath.Clean(r.file) == target {
+		return "", fragment
+	}
+	return target, fragment
+}
+
+// localRef returns the given JSON pointer of the main document when it points to a definition or
+// a response, depending on the given kind, and "" otherwise.
+func (r *resolver) localRef(fragment string, kind refKind) string {
+	var prefixes []string
+	switch kind {
+	case refKindSchema:
+		prefixes = refPrefixes
+		// Definitions nested under `$defs` are registered under their own key.
+		if strings.Contains(fragment, "/$defs/") {
+			return fragment
+		}
+	case refKindResponse:
+		prefixes = responseRefPrefixes
+	}
+	for _, prefix := range prefixes {
+		if name := strings.TrimPrefix("#"+fragment, prefix); name != "#"+fragment && !strings.Contains(name, "/") {
+			return fragment
+		}
+	}
+	return ""
+}
+
+// section returns the main document's definitions or responses, depending on the given kind,
+// alongside the prefix of their references.
+func (r *resolver) section(kind refKind) (map[string]interface{}, string) {
+	switch {
+	case kind == refKindResponse && r.doc.OpenAPI == "":
+		return r.doc.Responses, responseRefPrefixes[0]
+	case kind == refKindResponse:
+		return r.doc.Components.Responses, responseRefPrefixes[1]
+	case r.doc.OpenAPI == "":
+		return r.doc.Definitions, refPrefixes[0]
+	default:
+		return r.doc.Components.Schemas, refPrefixes[1]
+	}
+}
+
+// load returns the content of the given file ("" being the main document), referenced under the
+// given JSON pointer of the referencing file.
+func (r *resolver) load(file, referencingFile, ptr string) (Record, bool) {
+	if file == "" {
+		return r.root, true
+	}
+	if content, ok := r.files[file]; ok {
+		return content, content != nil
+	}
+	r.files[file] = nil
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		r.pc.errorf(r.location(referencingFile, ptr), "unreadable referenced file: %s", err)
+		return nil, false
+	}
+	r.pc.sources = append(r.pc.sources, b)
+	var content Record
+	if err := yaml.Unmarshal(b, &content); err != nil {
+		r.pc.errorf(r.location(referencingFile, ptr), "malformed referenced file: %s", err)
+		return nil, false
+	}
+	var node yamlv3.Node
+	if err := yamlv3.Unmarshal(b, &node); err == nil && len(node.Content) > 0 {
+		indexPositions(r.pc.positions, r.displayName(file)+"#", 0, node.Content[0])
+	}
+	r.files[file] = content
+	return content, true
+}
+
+// location returns the location of the given JSON pointer within the given file, as a reference
+// relative to the main document e.g., "common.yaml#/definitions/Address".
+func (r *resolver) location(file, ptr string) string {
+	if file == "" {
+		return ptr
+	}
+	return r.displayName(file) + ptr
+}
+
+// displayName returns the given file's path relative to the main document's directory.
+func (r *resolver) displayName(file string) string {
+	if rel, err := filepath.Rel(r.dir, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(file)
+}
+
+// checkAliasCycles reports definitions which merely reference one another in a loop.
+func (r *resolver) checkAliasCycles() {
+	defs, prefix := r.section(refKindSchema)
+	reported := make(map[string]bool)
+	for _, k := range sortedMapKeys(defs) {
+		chain := []string{k}
+		for current := k; ; {
+			def, ok := defs[current].(Record)
+			if !ok || len(def) != 1 {
+				break
+			}
+			ref, ok := def["$ref"].(string)
+			if !ok || !strings.HasPrefix(ref, prefix) {
+				break
+			}
+			current = strings.TrimPrefix(ref, prefix)
+			if current == k {
+				if !reported[k] {
+					for _, name := range chain {
+						reported[name] = true
+					}
+					r.pc.errorf(pointerTo(prefix[:len(prefix)-1], k, "$ref"), "reference cycle: %s -> %s", strings.Join(chain, " -> "), k)
+				}
+				break
+			}
+			if contains(chain, current) {
+				break
+			}
+			chain = append(chain, current)
+		}
+	}
+}
+
+// refName returns the name given to the definition found under the given JSON pointer of the given
+// file i.e., the pointer's last token or, failing that, the file's name.
+func refName(file, fragment string) string {
+	if tokens := strings.Split(fragment, "/"); len(tokens) > 1 && tokens[len(tokens)-1] != "" {
+		return unescapePointerToken(tokens[len(tokens)-1])
+	}
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+// resolvePointer returns the value found under the given JSON pointer of the given document.
+func resolvePointer(doc interface{}, fragment string) (interface{}, bool) {
+	if fragment == "" || fragment == "/" {
+		return doc, true
+	}
+	current := doc
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = unescapePointerToken(token)
+		switch currentTyped := current.(type) {
+		case Record:
+			found := false
+			for k, v := range currentTyped {
+				if fmt.Sprint(k) == token {
+					current, found = v, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(currentTyped) {
+				return nil, false
+			}
+			current = currentTyped[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// unescapePointerToken unescapes the given JSON pointer reference token.
+func unescapePointerToken(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// deepCopy returns a copy of the given YAML value, sharing no map or slice with it.
+func deepCopy(v interface{}) interface{} {
+	switch vTyped := v.(type) {
+	case Record:
+		result := make(Record, len(vTyped))
+		for k, val := range vTyped {
+			result[k] = deepCopy(val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(vTyped))
+		for i, val := range vTyped {
+			result[i] = deepCopy(val)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// sortedMapKeys returns the keys of the given map, sorted alphabetically.
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mapKeys returns the keys of the given map, as a set.
+func mapKeys(m map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool, len(m))
+	for k := range m {
+		keys[k] = true
+	}
+	return keys
+}
+
+// contains checks whether the given slice contains the given value.
+func contains(s []string, v string) bool {
+	for _, entry := range s {
+		if entry == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReferenceCycles(t *testing.T) {
+	tests := []struct {
+		name string
+		// The specification's files, by name; the main one is "api.yaml".
+		files map[string]string
+		// The expected problems' messages; none if the references are resolved.
+		want []string
+	}{
+		{
+			name: "recursive definition",
+			files: map[string]string{"api.yaml": `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Node:
+    type: object
+    properties:
+      next: {$ref: '#/definitions/Node'}
+`},
+		},
+		{
+			name: "aliased definitions",
+			files: map[string]string{"api.yaml": `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  A: {$ref: '#/definitions/B'}
+  B: {$ref: '#/definitions/C'}
+  C: {$ref: '#/definitions/A'}
+`},
+			want: []string{"reference cycle: A -> B -> C -> A"},
+		},
+		{
+			name: "inlined parameters",
+			files: map[string]string{"api.yaml": `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /a:
+    get:
+      operationId: getA
+      parameters:
+        - $ref: '#/parameters/P'
+      responses:
+        '200': {description: ok}
+parameters:
+  P: {$ref: '#/parameters/Q'}
+  Q: {$ref: '#/parameters/P'}
+`},
+			want: []string{"reference cycle: #/parameters/P -> #/parameters/Q -> #/parameters/P"},
+		},
+		{
+			name: "recursive definitions across files",
+			files: map[string]string{
+				"api.yaml": `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths: {}
+definitions:
+  Owner: {$ref: 'pets.yaml#/Owner'}
+`,
+				"pets.yaml": `
+Owner:
+  type: object
+  properties:
+    pet: {$ref: '#/Pet'}
+Pet:
+  type: object
+  properties:
+    owner: {$ref: '#/Owner'}
+`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := NewDocumentFromFile(filepath.Join(dir, "api.yaml"))
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("NewDocumentFromFile() error = %v, want none", err)
+				}
+				return
+			}
+			var errs ParseErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("NewDocumentFromFile() error = %v, want ParseErrors", err)
+			}
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				got = append(got, e.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NewDocumentFromFile() problems = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("NewDocumentFromFile() problem %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBundleFileReusableValues(t *testing.T) {
+	files := map[string]string{
+		"api.yaml": `
+openapi: 3.0.3
+info: {title: t, version: "1"}
+paths:
+  /members:
+    post:
+      operationId: createMember
+      parameters:
+        - $ref: '#/components/parameters/Team'
+      requestBody: {$ref: '#/components/requestBodies/Member'}
+      responses:
+        '200': {description: ok}
+components:
+  parameters:
+    Team: {name: team, in: query, schema: {$ref: 'common.yaml#/Team'}}
+  requestBodies:
+    Member:
+      content:
+        application/json:
+          schema: {$ref: 'common.yaml#/Member'}
+`,
+		"common.yaml": `
+Team: {type: string}
+Member:
+  type: object
+  properties:
+    name: {type: string}
+`,
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := BundleFile(filepath.Join(dir, "api.yaml"))
+	if err != nil {
+		t.Fatalf("BundleFile() error = %v", err)
+	}
+	// The reusable parameters and request bodies reference the bundled definitions.
+	if bytes.Contains(b, []byte("common.yaml")) {
+		t.Errorf("BundleFile() =\n%s\nwant no reference to other files", b)
+	}
+	for _, ref := range []string{"$ref: '#/components/schemas/Team'", "$ref: '#/components/schemas/Member'"} {
+		if bytes.Count(b, []byte(ref)) != 2 {
+			t.Errorf("BundleFile() =\n%s\nwant %s within both the components and the operation", b, ref)
+		}
+	}
+}
@@ -1,90 +1,205 @@
 package parser
 
 import (
-	"regexp"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 )
 
-var responseRegex = regexp.MustCompile(`([aA-zZ]+[a-z])(Create|Get|List|Update)([aA-zZ]+)?Response`)
+// PathResponse represents an operation's response for a given status code.
+type PathResponse struct {
+	// The response's status code (e.g. "200", "default").
+	Code string
+	// The response's description.
+	Description string
+	// The response's key within `Document.Responses`, when the response is defined there.
+	Ref string
+	// The response's schema.
+	Schema *DefinitionProperty
+}
 
-var returnsSnapshotTruthMap = map[string]bool{
-	"AccommodationsListResponse": true,
-	"GroupsListResponse":         true,
+// responseRefPrefixes are the prefixes of response references, by specification version.
+var responseRefPrefixes = []string{
+	"#/responses/",            // Swagger 2.
+	"#/components/responses/", // OpenAPI 3.
 }
 
-// parseIntoResponses maps swagger definitions into a new instance of `map[string]*Definition`,
-func parseIntoResponses(rawDefs map[string]interface{}) map[string]*Definition {
+// parseIntoResponses maps swagger definitions, found under the given JSON pointer, into a new
+// instance of `map[string]*Definition`.
+func parseIntoResponses(pc *parseContext, rawDefs map[string]interface{}, ptr string) map[string]*Definition {
 	respMap := make(map[string]*Definition)
 	for k, v := range rawDefs {
+		respPtr := pointerTo(ptr, k)
 		resp := &Definition{
 			Key:        strcase.ToCamel(k),
 			Properties: make([]*DefinitionProperty, 0),
 		}
-		if vTyped, ok := v.(Record); ok {
-			matches := responseRegex.FindStringSubmatch(resp.Key)
-			if len(matches) > 0 {
-				switch matches[2] {
-				case "Create":
-					if t := matches[1]; t != "GroupMembers" && t != "AccommodationResidents" {
-						resp.Returns = matches[1]
-					}
-				case "Get":
-					resp.Returns = matches[1] + matches[3]
-				case "List":
-					withSnapshot := ""
-					if val, ok := returnsSnapshotTruthMap[resp.Key]; ok && val {
-						withSnapshot = "Snapshot"
-					}
-					returns := matches[1][:len(matches[1])-1]
-					if resp.Key == "MembersListResponse" {
-						returns = "Dynamic" + returns // DynamicMember
-					}
-					resp.Returns = returns + withSnapshot + "[]"
-				case "Update":
-					if matches[1] != "AccommodationResidents" {
-						resp.Returns = "string"
-					}
+		vTyped, ok := v.(Record)
+		if !ok {
+			pc.unexpected(respPtr, "an object", v)
+			continue
+		}
+		resp.Extensions = parseExtensions(vTyped)
+		schemaRef := ""
+		if schema, ok := pc.recordAt(vTyped, "schema", respPtr); ok {
+			schemaRef = pc.refAt(schema, "$ref", pointerTo(respPtr, "schema"))
+		}
+		if headers, ok := pc.recordAt(vTyped, "headers", respPtr); ok {
+			// Keys which aren't part of the extended definition.
+			for _, propKey := range sortedRecordKeys(headers) {
+				prop := &DefinitionProperty{
+					Key: fmt.Sprint(propKey),
+					Ref: schemaRef,
 				}
-			}
-			if headers := vTyped["headers"]; headers != nil {
-				if headersTyped, ok := headers.(Record); ok {
-					// Keys which aren't part of the extended definition.
-					for propKey, propVal := range headersTyped {
-						prop := &DefinitionProperty{
-							Key: propKey.(string),
-						}
-						if propValTyped, ok := propVal.(Record); ok {
-							if propType := propValTyped["type"]; propType != nil {
-								prop.Type = propType.(string)
-							}
-							if propDesc := propValTyped["description"]; propDesc != nil {
-								matches := descriptionRegex.FindStringSubmatch(propDesc.(string))
-								if len(matches) >= 2 {
-									prop.Description = matches[1]
-								}
-							}
-						}
-						if schema := vTyped["schema"]; schema != nil {
-							if schemaTyped, ok := schema.(Record); ok {
-								if propRef := schemaTyped["$ref"]; propRef != nil {
-									prop.Ref = toRef(propRef.(string))
-								}
-							}
-						}
-						resp.Properties = append(resp.Properties, prop)
-					}
+				propPtr := pointerTo(respPtr, "headers", prop.Key)
+				propValTyped, ok := headers[propKey].(Record)
+				if !ok {
+					pc.unexpected(propPtr, "an object", headers[propKey])
+					continue
 				}
-			}
-			if schema := vTyped["schema"]; schema != nil {
-				if schemaTyped, ok := schema.(Record); ok {
-					if propRef := schemaTyped["$ref"]; propRef != nil {
-						resp.Ref = toRef(propRef.(string))
-					}
+				prop.Type, _, _ = pc.typeAt(propValTyped, "type", propPtr)
+				// OpenAPI 3 headers describe their type through a schema.
+				if propSchema, ok := pc.recordAt(propValTyped, "schema", propPtr); ok {
+					prop.Type, prop.Union, prop.Nullable = pc.typeAt(propSchema, "type", pointerTo(propPtr, "schema"))
 				}
+				prop.Description = extractDescription(pc.stringAt(propValTyped, "description", propPtr))
+				resp.Properties = append(resp.Properties, prop)
+			}
+		}
+		resp.Ref = schemaRef
+		// OpenAPI 3 responses describe their schema per media type.
+		if schema, schemaPtr := mediaTypeSchema(vTyped["content"], pointerTo(respPtr, "content")); schema != nil {
+			if schemaRef := pc.refAt(schema, "$ref", schemaPtr); schemaRef != "" {
+				resp.Ref = schemaRef
 			}
 		}
 		respMap[resp.Key] = resp
 	}
 	return respMap
 }
+
+// parseIntoPathResponses maps an operation's responses, found under the given JSON pointer, into a
+// new instance of `map[string]*PathResponse`, keyed by status code.
+func parseIntoPathResponses(pc *parseContext, rawResps Record, ptr string) map[string]*PathResponse {
+	respMap := make(map[string]*PathResponse)
+	for k, v := range rawResps {
+		resp := &PathResponse{
+			Code: fmt.Sprint(k),
+		}
+		respPtr := pointerTo(ptr, resp.Code)
+		vTyped, ok := v.(Record)
+		if !ok {
+			pc.unexpected(respPtr, "an object", v)
+			continue
+		}
+		resp.Description = pc.stringAt(vTyped, "description", respPtr)
+		if ref := pc.stringAt(vTyped, "$ref", respPtr); ref != "" {
+			resp.Ref = toResponseRef(ref)
+		}
+		schema, schemaPtr := mediaTypeSchema(vTyped["content"], pointerTo(respPtr, "content"))
+		if rawSchema, ok := pc.recordAt(vTyped, "schema", respPtr); ok {
+			schema, schemaPtr = rawSchema, pointerTo(respPtr, "schema")
+		}
+		if schema != nil {
+			resp.Schema = parseSchemaType(pc, schema, schemaPtr)
+		}
+		respMap[resp.Code] = resp
+	}
+	return respMap
+}
+
+// toResponseRef converts a response reference into its key within `Document.Responses`.
+func toResponseRef(s string) string {
+	for _, prefix := range responseRefPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			return strcase.ToCamel(strings.TrimPrefix(s, prefix))
+		}
+	}
+	return strcase.ToCamel(s)
+}
+
+// SuccessResponse returns the operation's success response i.e., its lowest 2xx response or,
+// failing that, its default response when it declares no error response; the default response of
+// operations declaring error responses describes errors as well, so they have none.
+func (p *Path) SuccessResponse() *PathResponse {
+	codes := make([]string, 0, len(p.Responses))
+	hasErrors := false
+	for code := range p.Responses {
+		switch {
+		case strings.HasPrefix(code, "2"):
+			codes = append(codes, code)
+		case strings.HasPrefix(code, "4"), strings.HasPrefix(code, "5"):
+			hasErrors = true
+		}
+	}
+	if len(codes) == 0 {
+		if hasErrors {
+			return nil
+		}
+		return p.Responses["default"]
+	}
+	sort.Strings(codes)
+	return p.Responses[codes[0]]
+}
+
+// resolveResponses links the document's operations to the document's responses:
+//   - inline success responses referencing a definition are registered as responses of their own
+//   - each response's returned entity is set from its schema's "data" property
+func resolveResponses(doc *Document) {
+	for _, k := range sortedPathKeys(doc.Paths) {
+		path := doc.Paths[k]
+		resp := path.SuccessResponse()
+		if resp == nil || resp.Ref != "" || resp.Schema == nil || resp.Schema.Ref == "" || resp.Schema.Type == "array" {
+			continue
+		}
+		key := strcase.ToCamel(path.Operation) + "Response"
+		if _, ok := doc.Responses[key]; ok {
+			key = strcase.ToCamel(path.Operation) + strcase.ToCamel(resp.Code) + "Response"
+		}
+		doc.Responses[key] = &Definition{
+			Key:         key,
+			Description: resp.Description,
+			Properties:  make([]*DefinitionProperty, 0),
+			Ref:         resp.Schema.Ref,
+		}
+		resp.Ref = key
+	}
+	for _, resp := range doc.Responses {
+		if resp.Returns == "" {
+			resp.Returns = returnsFromBody(doc.Definitions[resp.Ref])
+		}
+	}
+}
+
+// returnsFromBody returns the entity returned through the given response body's "data" property.
+func returnsFromBody(body *Definition) string {
+	if body == nil {
+		return ""
+	}
+	for _, prop := range body.Properties {
+		if prop.Key != "data" {
+			continue
+		}
+		switch {
+		case prop.Type == "array" && prop.Ref != "":
+			return prop.Ref + "[]"
+		case prop.Ref != "":
+			return prop.Ref
+		default:
+			return prop.Type
+		}
+	}
+	return ""
+}
+
+// sortedPathKeys returns the keys of the given paths, sorted alphabetically.
+func sortedPathKeys(paths map[string]*Path) []string {
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,13 +1,87 @@
 package parser
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
+// refPrefixes are the prefixes of schema references, by specification version.
+var refPrefixes = []string{
+	"#/definitions/",        // Swagger 2.
+	"#/components/schemas/", // OpenAPI 3.
+}
+
 // toRef strips the definition prefix from a type reference.
 func toRef(s string) string {
-	return strings.Replace(s, "#/definitions/", "", 1)
+	// Definitions nested under `$defs` are registered under their own key.
+	if idx := strings.LastIndex(s, "/$defs/"); idx >= 0 {
+		return s[idx+len("/$defs/"):]
+	}
+	for _, prefix := range refPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			return strings.TrimPrefix(s, prefix)
+		}
+	}
+	return s
+}
+
+// jsonMediaTypeRegex matches JSON media types such as "application/json" or
+// "application/problem+json".
+var jsonMediaTypeRegex = regexp.MustCompile(`^application/([a-z.\-]+\+)?json`)
+
+// mediaTypes returns the media types of an OpenAPI 3 content map, sorted alphabetically.
+func mediaTypes(content interface{}) []string {
+	contentTyped, ok := content.(Record)
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(contentTyped))
+	for k := range contentTyped {
+		if kTyped, ok := k.(string); ok {
+			result = append(result, kTyped)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// toStringSlice converts the given YAML sequence into a slice of strings.
+func toStringSlice(v interface{}) []string {
+	vTyped, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(vTyped))
+	for _, entry := range vTyped {
+		if entryTyped, ok := entry.(string); ok {
+			result = append(result, entryTyped)
+		}
+	}
+	return result
+}
+
+// mediaTypeSchema returns the schema of the most suitable media type from an OpenAPI 3 content
+// map, found under the given JSON pointer, alongside the schema's JSON pointer; JSON media types
+// are preferred.
+func mediaTypeSchema(content interface{}, ptr string) (Record, string) {
+	contentTyped, ok := content.(Record)
+	if !ok {
+		return nil, ""
+	}
+	keys := mediaTypes(contentTyped)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return jsonMediaTypeRegex.MatchString(keys[i]) && !jsonMediaTypeRegex.MatchString(keys[j])
+	})
+	for _, k := range keys {
+		if mediaType, ok := contentTyped[k].(Record); ok {
+			if schema, ok := mediaType["schema"].(Record); ok {
+				return schema, pointerTo(ptr, k, "schema")
+			}
+		}
+	}
+	return nil, ""
 }
 
 var descriptionRegex = regexp.MustCompile(`^(.*\.)`)
@@ -20,3 +94,157 @@ func extractDescription(s string) string {
 	}
 	return ""
 }
+
+// parseType parses a schema's type, which may be expressed as a list of types (OpenAPI 3.1 only).
+//
+// @returns (t, union, nullable): t -> the first non-null type, union -> every non-null type when
+// there are more than one, nullable -> whether "null" is an accepted type
+func parseType(v interface{}) (string, []string, bool) {
+	switch vTyped := v.(type) {
+	case string:
+		return vTyped, nil, vTyped == "null"
+	case []interface{}:
+		types := make([]string, 0, len(vTyped))
+		nullable := false
+		for _, entry := range vTyped {
+			entryTyped, ok := entry.(string)
+			if !ok {
+				continue
+			}
+			if entryTyped == "null" {
+				nullable = true
+				continue
+			}
+			types = append(types, entryTyped)
+		}
+		switch {
+		case len(types) == 0 && nullable:
+			// A type array made of "null" only is the null type.
+			return "null", nil, true
+		case len(types) == 0:
+			return "", nil, false
+		case len(types) == 1:
+			return types[0], nil, nullable
+		default:
+			return types[0], types, nullable
+		}
+	default:
+		return "", nil, false
+	}
+}
+
+// typeOfValue returns the schema type matching the given value.
+func typeOfValue(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case Record:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// parseSchemaType maps a nested schema, such as a tuple item, found under the given JSON pointer
+// into a `DefinitionProperty` describing its type.
+func parseSchemaType(pc *parseContext, v interface{}, ptr string) *DefinitionProperty {
+	prop := &DefinitionProperty{
+		Validation: &DefinitionPropertyValidation{},
+	}
+	vTyped, ok := v.(Record)
+	if !ok {
+		pc.unexpected(ptr, "an object", v)
+		return prop
+	}
+	prop.Type, prop.Union, prop.Nullable = pc.typeAt(vTyped, "type", ptr)
+	prop.Ref = pc.refAt(vTyped, "$ref", ptr)
+	if ref, nullable, ok := singleRef(vTyped); ok {
+		prop.Ref = ref
+		prop.Nullable = prop.Nullable || nullable
+	}
+	prop.Format = pc.stringAt(vTyped, "format", ptr)
+	prop.Extensions = parseExtensions(vTyped)
+	if schemaConst, ok := vTyped["const"]; ok {
+		prop.Const = normalizeValue(schemaConst)
+		if prop.Type == "" {
+			prop.Type = typeOfValue(schemaConst)
+		}
+	}
+	if schemaItems, ok := pc.recordAt(vTyped, "items", ptr); ok {
+		itemsPtr := pointerTo(ptr, "items")
+		if itemsRef := pc.refAt(schemaItems, "$ref", itemsPtr); itemsRef != "" {
+			prop.Ref = itemsRef
+		}
+		if itemsType, _, _ := pc.typeAt(schemaItems, "type", itemsPtr); itemsType != "" {
+			prop.Ref = itemsType
+		}
+	}
+	if schemaAdditional := vTyped["additionalProperties"]; schemaAdditional != nil {
+		if prop.AdditionalProperties = parseAdditionalProperties(pc, schemaAdditional, pointerTo(ptr, "additionalProperties")); prop.AdditionalProperties != nil {
+			prop.Type = "object"
+		}
+	}
+	return prop
+}
+
+// parseAdditionalProperties maps the `additionalProperties` keyword of a schema, found under the
+// given JSON pointer, into the type of its map values; values of any type are described by an
+// untyped property, whereas `nil` is returned when additional properties are disallowed.
+func parseAdditionalProperties(pc *parseContext, v interface{}, ptr string) *DefinitionProperty {
+	switch vTyped := v.(type) {
+	case bool:
+		if !vTyped {
+			return nil
+		}
+		return &DefinitionProperty{
+			Validation: &DefinitionPropertyValidation{},
+		}
+	default:
+		return parseSchemaType(pc, vTyped, ptr)
+	}
+}
+
+// parseExamples returns the example values of the given schema, from either the `examples`
+// (OpenAPI 3.1) or the `example` keyword.
+func parseExamples(schema Record) []interface{} {
+	if examples, ok := schema["examples"].([]interface{}); ok {
+		result := make([]interface{}, 0, len(examples))
+		for _, example := range examples {
+			result = append(result, normalizeValue(example))
+		}
+		return result
+	}
+	if example, ok := schema["example"]; ok {
+		return []interface{}{normalizeValue(example)}
+	}
+	return nil
+}
+
+// normalizeValue converts the given YAML value into a value which can be encoded as JSON i.e.,
+// `Record` maps are converted into `map[string]interface{}`.
+func normalizeValue(v interface{}) interface{} {
+	switch vTyped := v.(type) {
+	case Record:
+		result := make(map[string]interface{}, len(vTyped))
+		for k, val := range vTyped {
+			result[fmt.Sprint(k)] = normalizeValue(val)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(vTyped))
+		for _, val := range vTyped {
+			result = append(result, normalizeValue(val))
+		}
+		return result
+	default:
+		return v
+	}
+}
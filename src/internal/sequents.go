@@ -6,22 +6,10 @@ import (
 	"openapi-generator/internal/parser"
 )
 
-// HasConstructor checks whether the given key has a constructor.
-func HasConstructor(ref string) bool {
-	switch ref {
-	case
-		"APIError",
-		"Colour",
-		"EntityType",
-		"ViewEntityType",
-		"ErrorCode",
-		"ErrorType",
-		"RelationshipWithMember",
-		"MemberRole":
-		return false
-	default:
-		return true
-	}
+// HasConstructor checks whether the type referenced by the given property has a constructor i.e.,
+// whether it isn't an enumeration, as resolved by the parser from the referenced definition.
+func HasConstructor(prop *parser.DefinitionProperty) bool {
+	return !prop.Enum
 }
 
 // IsErrorType checks whether the given key is an error type.
@@ -29,14 +17,62 @@ func IsErrorType(key string) bool {
 	return strings.Contains(strings.ToLower(key), "error")
 }
 
-// IsPathParameter checks whether the givens properties correspond to a path parameter.
-func IsPathParameter(props []*parser.DefinitionProperty) bool {
-	return len(props) == 1 && props[0].In != "path"
+// IsSuitedForAPIMethod checks whether the given properties are suited for an API method i.e.,
+// whether they describe a request payload.
+func IsSuitedForAPIMethod(props []*parser.DefinitionProperty) bool {
+	for _, prop := range props {
+		if prop.In == "body" || prop.In == "formData" {
+			return true
+		}
+	}
+	return false
 }
 
-// IsSuitedForAPIMethod checks whether the given properties are suited for an API method.
-func IsSuitedForAPIMethod(props []*parser.DefinitionProperty) bool {
-	return len(props) > 1 || IsPathParameter(props)
+const (
+	// PayloadEncodingJSON represents a payload sent as "application/json".
+	PayloadEncodingJSON = "json"
+	// PayloadEncodingForm represents a payload sent as "application/x-www-form-urlencoded".
+	PayloadEncodingForm = "form"
+	// PayloadEncodingMultipart represents a payload sent as "multipart/form-data".
+	PayloadEncodingMultipart = "multipart"
+)
+
+// PayloadEncoding returns the encoding of the given operation's payload, as per its parameters
+// and request media types.
+func PayloadEncoding(path *parser.Path) string {
+	formParams := FilterParameters(path.Parameters, "formData")
+	for _, param := range formParams {
+		if IsBinaryProperty(param) {
+			return PayloadEncodingMultipart
+		}
+	}
+	hasMediaType := func(prefix string) bool {
+		for _, mediaType := range path.Consumes {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case len(formParams) > 0 && hasMediaType("multipart/form-data"):
+		return PayloadEncodingMultipart
+	case len(formParams) > 0:
+		return PayloadEncodingForm
+	case len(path.Consumes) == 0 || hasMediaType("application/json"):
+		return PayloadEncodingJSON
+	case hasMediaType("multipart/form-data"):
+		return PayloadEncodingMultipart
+	case hasMediaType("application/x-www-form-urlencoded"):
+		return PayloadEncodingForm
+	default:
+		return PayloadEncodingJSON
+	}
+}
+
+// IsBinaryProperty checks whether the given property holds binary content i.e., a file.
+func IsBinaryProperty(prop *parser.DefinitionProperty) bool {
+	return prop.Type == "file" || prop.Format == "binary"
 }
 
 // IsPropSuitableForValidation checks whether the given property is suitable for validation i.e., if the given value
@@ -50,22 +86,41 @@ func IsPropSuitableForValidation(t string) bool {
 	}
 }
 
+// IsNestedObject checks whether the given property references an object definition, such as an
+// inline object schema hoisted by the parser.
+func IsNestedObject(defs map[string]*parser.Definition, prop *parser.DefinitionProperty) bool {
+	if prop.Type != "" || prop.Ref == "" {
+		return false
+	}
+	def, ok := defs[prop.Ref]
+	return ok && def.Type == "object"
+}
+
 // IsPrimitiveType checks whether the given type is a primitive type i.e., not a custom model.
 func IsPrimitiveType(t string) bool {
 	switch t {
-	case "string", "integer", "array":
+	case "string", "integer", "number", "boolean", "array":
 		return true
 	default:
 		return false
 	}
 }
 
-// IsPaginatedResponse checks whether the given type is a paginated response.
-func IsPaginatedResponse(key string) bool {
-	switch key {
-	case "listMembersResponseBody":
-		return true
-	default:
-		return false
+// IsPaginatedResponse checks whether the given response body is paginated, as per its
+// "x-paginated" extension.
+func IsPaginatedResponse(def *parser.Definition) bool {
+	return def.Extensions.Bool(ExtensionPaginated)
+}
+
+// PathParamAlias returns the name given to the given path parameter of the given operation within
+// generated code i.e., its "x-param-alias" extension, if any.
+func PathParamAlias(path *parser.Path, key string) string {
+	for _, param := range path.Parameters {
+		if param.In == "path" && param.Key == key {
+			if alias := param.Extensions.String(ExtensionParamAlias); alias != "" {
+				return alias
+			}
+		}
 	}
+	return key
 }
@@ -33,48 +33,122 @@ func SortSliceKeysAlphabetically(keys []string) []string {
 	return keys
 }
 
-// SortKeysByCase returns a case-sorted slice of keys.
+// SortKeysByCase returns a case-sorted slice of keys i.e., lower case keys precede the others; the
+// keys' order is otherwise preserved.
 func SortKeysByCase(keys []string) []string {
-	sort.Slice(keys, func(i, j int) bool {
-		first := keys[i][:1]
-		if first == strings.ToLower(first) {
-			return true
-		}
-		return false
+	isLower := func(k string) bool {
+		return k != "" && k[:1] == strings.ToLower(k[:1])
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return isLower(keys[i]) && !isLower(keys[j])
 	})
 	return keys
 }
 
-// MapByPkg returns the map with keys matching the different core packages.
-func MapByPkg(paths map[string]*parser.Path) map[string][]*parser.Path {
-	result := make(map[string][]*parser.Path)
-	for k, v := range paths {
-		key := strings.Split(k, ".")[0]
-		if slice, ok := result[key]; ok {
-			result[key] = append(slice, v)
-		} else {
-			result[key] = []*parser.Path{v}
+// SortKeysByInheritance returns the given definition keys such that parents precede their
+// children (allOf); the keys' order is otherwise preserved.
+func SortKeysByInheritance(keys []string, defs map[string]*parser.Definition) []string {
+	included := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		included[k] = true
+	}
+	visited := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	var visit func(k string)
+	visit = func(k string) {
+		if visited[k] {
+			return
 		}
+		visited[k] = true
+		if def, ok := defs[k]; ok {
+			for _, parent := range def.Extends {
+				if included[parent] {
+					visit(parent)
+				}
+			}
+		}
+		result = append(result, k)
+	}
+	for _, k := range keys {
+		visit(k)
 	}
 	return result
 }
 
-// SortProperties will sort the given properties by:
+// Order represents the order in which properties and operations are generated.
+type Order string
+
+const (
+	// OrderSorted sorts properties as per `SortProperties`, and operations by path then verb.
+	OrderSorted Order = "sorted"
+	// OrderSpec keeps the declaration order of properties and operations.
+	OrderSpec Order = "spec"
+)
+
+// Properties returns the given properties as per the order, either sorted by `SortProperties` or
+// as given, on the basis that they are declaration-ordered (see `OrderDefinitions`); the given
+// slice is left untouched.
+func (o Order) Properties(props []*parser.DefinitionProperty) []*parser.DefinitionProperty {
+	if o == OrderSpec {
+		return append(make([]*parser.DefinitionProperty, 0, len(props)), props...)
+	}
+	return SortProperties(props)
+}
+
+// Paths returns the given operations as per the order.
+func (o Order) Paths(paths map[string]*parser.Path) []*parser.Path {
+	result := make([]*parser.Path, 0, len(paths))
+	for _, path := range paths {
+		result = append(result, path)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if o == OrderSpec && result[i].Order != result[j].Order {
+			return result[i].Order < result[j].Order
+		}
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].HTTPVerb < result[j].HTTPVerb
+	})
+	return result
+}
+
+// OrderDefinitions sorts the properties of the given definitions in declaration order, under
+// `OrderSpec`; the definitions are otherwise left as is i.e., with their properties sorted by name.
+func (o Order) OrderDefinitions(defs map[string]*parser.Definition) {
+	if o != OrderSpec {
+		return
+	}
+	for _, def := range defs {
+		sort.SliceStable(def.Properties, func(i, j int) bool {
+			return def.Properties[i].Order < def.Properties[j].Order
+		})
+	}
+}
+
+// SortProperties returns the given properties sorted by:
 // - placing entity Identifiers at the top
 // - placing timestamps at the bottom
 // - otherwise, alphabetically
+//
+// The given slice is left untouched.
 func SortProperties(props []*parser.DefinitionProperty) []*parser.DefinitionProperty {
-	sort.Slice(props, func(i, j int) bool {
-		// Always place "id" property first.
-		if props[i].Key == "id" {
-			return true
+	rank := func(prop *parser.DefinitionProperty) int {
+		switch {
+		case prop.Key == "id":
+			return 0
+		case strings.HasSuffix(prop.Key, "_at"):
+			return 2
+		default:
+			return 1
 		}
-		// Always place timestamps last.
-		if strings.HasSuffix(props[i].Key, "_at") {
-			return false
+	}
+	result := append(make([]*parser.DefinitionProperty, 0, len(props)), props...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if ri, rj := rank(result[i]), rank(result[j]); ri != rj {
+			return ri < rj
 		}
-		// Otherwise, sort alphabetically.
-		return props[i].Key < props[j].Key
+		return result[i].Key < result[j].Key
 	})
-	return props
+	return result
 }
@@ -0,0 +1,238 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines surrounding each change.
+const context = 3
+
+// maxEdits is the number of edits past which texts are reported as entirely replaced, so as to
+// bound the diff's cost.
+const maxEdits = 2000
+
+// opKind represents the kind of an edit.
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op represents an edit of a single line.
+type op struct {
+	kind opKind
+	// The line's index within the former text, or that of the next line when inserted.
+	from int
+	// The line's index within the latter text, or that of the next line when deleted.
+	to   int
+	line string
+}
+
+// Unified returns the unified diff turning the given former text into the latter, whose files are
+// named as given e.g., "a/index.ts"; identical texts yield an empty diff.
+func Unified(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(ops); {
+		// Find the next change.
+		for i < len(ops) && ops[i].kind == opEqual {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		// Changes separated by few enough unchanged lines share their hunk.
+		start, end := max(0, i-context), i
+		for {
+			for end < len(ops) && ops[end].kind != opEqual {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		stop := min(len(ops), end+context)
+		writeHunk(&b, ops[start:stop])
+		i = stop
+	}
+	return b.String()
+}
+
+// writeHunk writes the hunk made of the given edits.
+func writeHunk(b *strings.Builder, ops []op) {
+	fromCount, toCount := 0, 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			fromCount++
+		}
+		if o.kind != opDelete {
+			toCount++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(ops[0].from, fromCount), hunkRange(ops[0].to, toCount))
+	for _, o := range ops {
+		prefix := " "
+		switch o.kind {
+		case opDelete:
+			prefix = "-"
+		case opInsert:
+			prefix = "+"
+		}
+		b.WriteString(prefix + o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange returns the range of a hunk's side, starting at the given line index e.g., "3,7".
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// Empty ranges designate the line preceding them.
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines returns the lines of the given text, with their line feed.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the edits turning the given former lines into the latter; their common prefix
+// and suffix are set aside before diffing the rest.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, from: i, to: i, line: a[i]})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for i := suffix; i > 0; i-- {
+		ops = append(ops, op{kind: opEqual, from: len(a) - i, to: len(b) - i, line: a[len(a)-i]})
+	}
+	return ops
+}
+
+// myers returns the shortest edits turning the given former lines into the latter, found as per
+// Myers' algorithm; the lines' indexes are offset by the given ones.
+func myers(a, b []string, fromOffset, toOffset int) []op {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	// The furthest former line reached on each diagonal, after each number of edits.
+	trace := make([][]int, 0)
+	v := make([]int, 2*limit+3)
+	offset := limit + 1
+	found := n+m == 0
+	for d := 0; d <= limit && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return replaceAll(a, b, fromOffset, toOffset)
+	}
+
+	// Backtrack from the end, through the recorded diagonals.
+	reversed := make([]op, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		at := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, op{kind: opEqual, from: fromOffset + x, to: toOffset + y, line: a[x]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			reversed = append(reversed, op{kind: opInsert, from: fromOffset + x, to: toOffset + prevY, line: b[prevY]})
+		} else {
+			reversed = append(reversed, op{kind: opDelete, from: fromOffset + prevX, to: toOffset + y, line: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	ops := make([]op, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		ops = append(ops, reversed[i])
+	}
+	return ops
+}
+
+// replaceAll returns the edits deleting every given former line, then inserting every latter one.
+func replaceAll(a, b []string, fromOffset, toOffset int) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	for i, line := range a {
+		ops = append(ops, op{kind: opDelete, from: fromOffset + i, to: toOffset, line: line})
+	}
+	for i, line := range b {
+		ops = append(ops, op{kind: opInsert, from: fromOffset + len(a), to: toOffset + i, line: line})
+	}
+	return ops
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
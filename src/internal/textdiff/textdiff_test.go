@@ -0,0 +1,145 @@
+package textdiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "identical",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "created file",
+			from: "",
+			to:   "a\nb\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "removed file",
+			from: "a\nb\n",
+			to:   "",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "distant changes",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name: "close changes",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\neight\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+		{
+			name: "missing final line feed",
+			from: "a\nb",
+			to:   "a\nb\n",
+			want: "--- a/f\n+++ b/f\n" +
+				"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("a/f", "b/f", tt.from, tt.to)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if got != "" {
+				if patched := apply(t, tt.from, got); patched != tt.to {
+					t.Errorf("Unified() applied = %q, want %q", patched, tt.to)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedReplacement(t *testing.T) {
+	// Texts differing by more than maxEdits lines are reported as replaced, and remain applicable.
+	var from, to strings.Builder
+	for i := 0; i < maxEdits; i++ {
+		fmt.Fprintf(&from, "from %d\n", i)
+		fmt.Fprintf(&to, "to %d\n", i)
+		fmt.Fprintf(&to, "common %d\n", i)
+		fmt.Fprintf(&from, "common %d\n", i)
+	}
+	got := Unified("a/f", "b/f", from.String(), to.String())
+	if patched := apply(t, from.String(), got); patched != to.String() {
+		t.Error("Unified() isn't applicable")
+	}
+}
+
+// apply returns the given text patched as per the given unified diff.
+func apply(t *testing.T, text, diff string) string {
+	t.Helper()
+	from := splitLines(text)
+	lines := strings.SplitAfter(diff, "\n")
+	var result strings.Builder
+	next, inserted := 0, false
+	for i := 2; i < len(lines) && lines[i] != ""; i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			start, err := strconv.Atoi(strings.Split(strings.TrimPrefix(strings.Fields(line)[1], "-"), ",")[0])
+			if err != nil {
+				t.Fatalf("malformed hunk header %q", line)
+			}
+			// Empty ranges designate the line preceding them.
+			if !strings.HasSuffix(strings.Fields(line)[1], ",0") {
+				start--
+			}
+			for ; next < start; next++ {
+				result.WriteString(from[next])
+			}
+		case strings.HasPrefix(line, `\ `):
+			// The preceding line has no line feed; the lines of the former text are copied as is.
+			if inserted {
+				s := strings.TrimSuffix(result.String(), "\n")
+				result.Reset()
+				result.WriteString(s)
+			}
+		case strings.HasPrefix(line, "+"):
+			result.WriteString(line[1:])
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, " "):
+			if strings.TrimSuffix(from[next], "\n") != strings.TrimSuffix(line[1:], "\n") {
+				t.Fatalf("line %d = %q, diff expects %q", next+1, from[next], line[1:])
+			}
+			if line[0] == ' ' {
+				result.WriteString(from[next])
+			}
+			next++
+		default:
+			t.Fatalf("malformed diff line %q", line)
+		}
+		inserted = strings.HasPrefix(line, "+")
+	}
+	for ; next < len(from); next++ {
+		result.WriteString(from[next])
+	}
+	return result.String()
+}
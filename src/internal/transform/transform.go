@@ -0,0 +1,179 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openapi-generator/internal"
+	"openapi-generator/internal/parser"
+)
+
+// Options represents the options of the passes, as per the target generated.
+type Options struct {
+	// The names given to definitions and properties, keyed as per `internal.ApplyOverrides`.
+	Names map[string]string
+	// The types given to properties, keyed as per `internal.ApplyOverrides`.
+	Types map[string]string
+	// The extension through which the target's generator names definitions and properties e.g.,
+	// "x-ts-name"; empty if it doesn't support renaming.
+	NameExtension string
+	// The extension through which the target's generator types properties e.g., "x-ts-type"; empty
+	// if it doesn't support overriding types.
+	TypeExtension string
+}
+
+// Pass represents a transformation of a document.
+type Pass struct {
+	// The pass' name, by which it's enabled or disabled e.g., "rename".
+	Name string
+	// The pass' description.
+	Description string
+	// Whether the pass runs unless disabled.
+	Default bool
+	// Apply returns a transformed copy of the given document, which is left untouched.
+	Apply func(doc *parser.Document, opts *Options) (*parser.Document, error)
+}
+
+// Passes are the transformation passes, in order of application.
+var Passes = []*Pass{
+	{
+		Name:        "overrides",
+		Description: "Set the configured names and types as specification extensions.",
+		Default:     true,
+		Apply:       applyOverrides,
+	},
+	{
+		Name:        "rename",
+		Description: "Rename definitions and properties as per their name extension, alongside their references.",
+		Default:     true,
+		Apply:       renameDefinitions,
+	},
+	{
+		Name:        "response-bodies",
+		Description: "Move the definitions whose name ends with \"ResponseBody\" to the responses.",
+		Default:     true,
+		Apply:       moveResponseBodies,
+	},
+}
+
+// IsPass checks whether there is a pass of the given name.
+func IsPass(name string) bool {
+	for _, p := range Passes {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the passes, in order of application.
+func Names() []string {
+	names := make([]string, 0, len(Passes))
+	for _, p := range Passes {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Enabled returns the names of the passes enabled as per the given toggles, by name, in order of
+// application; passes which aren't toggled are enabled if they run by default.
+func Enabled(toggles map[string]bool) []string {
+	names := make([]string, 0, len(Passes))
+	for _, p := range Passes {
+		if p.enabled(toggles) {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// enabled checks whether the pass is enabled as per the given toggles, by name.
+func (p *Pass) enabled(toggles map[string]bool) bool {
+	if enabled, ok := toggles[p.Name]; ok {
+		return enabled
+	}
+	return p.Default
+}
+
+// Run returns a copy of the given document, transformed by the passes enabled as per the given
+// toggles, by name; passes which aren't toggled run if they do by default.
+func Run(doc *parser.Document, toggles map[string]bool, opts *Options) (*parser.Document, error) {
+	unknown := make([]string, 0)
+	for name := range toggles {
+		if !IsPass(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown pass(es) %s, expected one of %v", strings.Join(unknown, ", "), Names())
+	}
+
+	result := doc
+	for _, p := range Passes {
+		if !p.enabled(toggles) {
+			continue
+		}
+		var err error
+		if result, err = p.Apply(result, opts); err != nil {
+			return nil, fmt.Errorf("pass '%s': %w", p.Name, err)
+		}
+	}
+	if result == doc {
+		return doc.Copy(), nil
+	}
+	return result, nil
+}
+
+// applyOverrides sets the overrides' names and types as the document's name and type extensions.
+func applyOverrides(doc *parser.Document, opts *Options) (*parser.Document, error) {
+	result := doc.Copy()
+	overrides := []struct {
+		values map[string]string
+		ext    string
+		kind   string
+	}{
+		{opts.Names, opts.NameExtension, "name"},
+		{opts.Types, opts.TypeExtension, "type"},
+	}
+	for _, o := range overrides {
+		if len(o.values) == 0 {
+			continue
+		}
+		if o.ext == "" {
+			return nil, fmt.Errorf("the target doesn't support %s overrides", o.kind)
+		}
+		if err := internal.ApplyOverrides(result, o.values, o.ext); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// renameDefinitions renames the document's definitions and properties as per the name extension.
+func renameDefinitions(doc *parser.Document, opts *Options) (*parser.Document, error) {
+	result := doc.Copy()
+	if opts.NameExtension != "" {
+		if err := internal.RenameDefinitions(result, opts.NameExtension); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// moveResponseBodies moves the document's definitions whose key ends with "ResponseBody" to its
+// responses.
+func moveResponseBodies(doc *parser.Document, _ *Options) (*parser.Document, error) {
+	result := doc.Copy()
+	if result.Responses == nil {
+		result.Responses = make(map[string]*parser.Definition)
+	}
+	for k, v := range result.Definitions {
+		if strings.HasSuffix(k, "ResponseBody") {
+			result.Responses[k] = v
+			delete(result.Definitions, k)
+		}
+	}
+	return result, nil
+}
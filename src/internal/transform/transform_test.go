@@ -0,0 +1,88 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-generator/internal/parser"
+)
+
+const spec = `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members:
+    get:
+      operationId: listMembers
+      responses:
+        '200':
+          description: ok
+          schema: {$ref: '#/definitions/ListMembersResponseBody'}
+definitions:
+  Member:
+    type: object
+    x-ts-name: Person
+    properties:
+      country_code: {type: string}
+      created_at: {type: string, format: date-time}
+      role: {type: string, enum: [admin, user]}
+  ListMembersResponseBody:
+    type: object
+    properties:
+      data:
+        type: array
+        items: {$ref: '#/definitions/Member'}
+`
+
+func TestRunLeavesDocumentUntouched(t *testing.T) {
+	opts := &Options{
+		Names:         map[string]string{"Member.country_code": "country", "Role": "MemberRole"},
+		Types:         map[string]string{"Member.created_at": "ExtendedDate"},
+		NameExtension: "x-ts-name",
+		TypeExtension: "x-ts-type",
+	}
+	tests := []struct {
+		name    string
+		toggles map[string]bool
+		// The definition expected within the result, as a proof that the passes did run.
+		wantDef string
+	}{
+		{name: "default", wantDef: "MemberRole"},
+		{name: "all disabled", toggles: map[string]bool{"overrides": false, "rename": false, "response-bodies": false}, wantDef: "Role"},
+		{name: "rename only", toggles: map[string]bool{"overrides": false, "response-bodies": false}, wantDef: "Person"},
+		{name: "overrides only", toggles: map[string]bool{"rename": false, "response-bodies": false}, wantDef: "Member"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.NewDocument([]byte(spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := doc.Copy()
+
+			result, err := Run(doc, tt.toggles, opts)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result == doc {
+				t.Fatal("Run() returned the given document, want a copy")
+			}
+			if _, ok := result.Definitions[tt.wantDef]; !ok {
+				t.Errorf("Run() result lacks definition '%s'", tt.wantDef)
+			}
+			if !reflect.DeepEqual(doc, before) {
+				t.Error("Run() altered the given document")
+			}
+		})
+	}
+}
+
+func TestRunUnknownPass(t *testing.T) {
+	doc, err := parser.NewDocument([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Run(doc, map[string]bool{"inline": true}, &Options{}); err == nil {
+		t.Fatal("Run() error = nil, want an unknown pass error")
+	}
+}
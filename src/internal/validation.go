@@ -4,8 +4,8 @@ const (
 	ValidationMessageRequired  = "This field is required."
 	ValidationMessageMaxLength = "This field allows a maximum of %[1]d characters."
 	ValidationMessageMinLength = "This field requires a minimum of %[1]d characters."
-	ValidationMessageMin       = "This field requires a minimum of %[1]d."
-	ValidationMessageMax       = "This field allows a maximum of %[1]d."
+	ValidationMessageMin       = "This field requires a minimum of %[1]v."
+	ValidationMessageMax       = "This field allows a maximum of %[1]v."
 	ValidationMessageMinItems  = "This field requires a minimum of %[1]d item."
 	ValidationMessageMaxItems  = "This field allows a maximum of %[1]d item(s)."
 	ValidationMessageEmail     = "This field must be a valid email address."
@@ -1,57 +1,106 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"openapi-generator/gen"
 	"os"
-	"strings"
+
+	"openapi-generator/gen"
+	// Built-in generators, which register themselves.
+	_ "openapi-generator/gen/typescript"
 )
 
 const VERSION = "0.1.0"
 
+// The exit codes of the program.
+const (
+	// exitOK reports a success.
+	exitOK = 0
+	// exitFailure reports any other failure e.g., breaking changes found by `diff`.
+	exitFailure = 1
+	// exitUsage reports an invalid command, flag or configuration.
+	exitUsage = 2
+	// exitSpec reports an invalid specification, or problems found by `lint`.
+	exitSpec = 3
+	// exitWrite reports a failure to write the output.
+	exitWrite = 4
+)
+
 func main() {
 	// README:
 	// ---
 	// Q: Why not simply include the execution code inside `main`?:
 	// A: The execution code is contained inside a func (`run`) to enable a single point of return. By doing so,
 	// we are able to define a centralised error/success handling pattern.
-	if err := run(); err != nil {
-		fmt.Println(err)
-		os.Exit(1) // Exit with error.
+	if err := run(os.Args[1:]); err != nil {
+		var usageErr *usageError
+		if !errors.Is(err, flag.ErrHelp) && !(errors.As(err, &usageErr) && usageErr.reported) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitCode(err)) // Exit with error.
 	}
-	fmt.Println("Done.")
 }
 
-const specFileName = ""
-
-func run() error {
-	// Parse file extension flag.
-	var extnFlag string
-	{
-		flag.StringVar(&extnFlag, "extension", "", "Extension to use for output files")
-		flag.Parse()
-
-		if extnFlag == "" {
-			return fmt.Errorf("error: an extension must be specified")
-		} else if !strings.HasPrefix(extnFlag, ".") {
-			extnFlag = "." + extnFlag
+func run(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return &usageError{Err: errors.New("error: a command must be specified")}
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			printUsage()
+			return nil
 		}
+		printUsage()
+		return &usageError{Err: fmt.Errorf("error: unknown command '%s'", args[0])}
 	}
+	return cmd.run(newFlagSet(args[0], cmd), args[1:])
+}
 
-	// Open and read specification file.
-	specFile, err := os.Open("../../openapi/" + specFileName)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = specFile.Close()
-	}()
-	b, err := io.ReadAll(specFile)
-	if err != nil {
-		return err
+// usageError represents an invalid command, flag or configuration.
+type usageError struct {
+	Err error
+	// Whether the error has been reported already e.g., by a flag set.
+	reported bool
+}
+
+func (e *usageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.Err
+}
+
+// specFailure represents a specification found to have problems e.g., by `lint`.
+type specFailure struct {
+	Err error
+}
+
+func (e *specFailure) Error() string {
+	return e.Err.Error()
+}
+
+// exitCode returns the exit code reporting the given error.
+func exitCode(err error) int {
+	var (
+		usageErr *usageError
+		specErr  *gen.SpecError
+		specFail *specFailure
+		writeErr *gen.WriteError
+	)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &specErr), errors.As(err, &specFail):
+		return exitSpec
+	case errors.As(err, &writeErr):
+		return exitWrite
+	default:
+		return exitFailure
 	}
-	// Generate.
-	return gen.New(b, VERSION, gen.Extension(extnFlag))
 }
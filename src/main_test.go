@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile writes the given content to the named file of the given directory, and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	spec := writeFile(t, dir, "api.yaml", `
+swagger: "2.0"
+info: {title: t, version: "1"}
+paths:
+  /members:
+    get:
+      operationId: listMembers
+      responses:
+        '200': {description: ok}
+`)
+	invalid := writeFile(t, dir, "invalid.yaml", `
+swagger: "2.0"
+info: {title: t, version: "1"}
+definitions:
+  Member: {$ref: "#/definitions/Missing"}
+`)
+	breaking := writeFile(t, dir, "breaking.yaml", "swagger: \"2.0\"\ninfo: {title: t, version: \"1\"}\npaths: {}\n")
+	notDir := writeFile(t, dir, "file", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"build"}, exitUsage},
+		{"help", []string{"help"}, exitOK},
+		{"version", []string{"version"}, exitOK},
+		{"unknown flag", []string{"validate", "--inptu", spec}, exitUsage},
+		{"no input", []string{"validate", "--config", ""}, exitUsage},
+		{"valid", []string{"validate", "--input", spec}, exitOK},
+		{"invalid", []string{"validate", "--input", invalid}, exitSpec},
+		{"missing", []string{"validate", "--input", filepath.Join(dir, "missing.yaml")}, exitSpec},
+		{"diff arguments", []string{"diff", spec}, exitUsage},
+		{"no changes", []string{"diff", spec, spec}, exitOK},
+		{"breaking changes", []string{"diff", spec, breaking}, exitFailure},
+		{"unknown target", []string{"generate", "--input", spec, "--target", "cobol", "--output", dir}, exitUsage},
+		{"generate", []string{"generate", "--input", spec, "--target", "typescript", "--output", filepath.Join(dir, "out")}, exitOK},
+		{"unwritable output", []string{"generate", "--input", spec, "--target", "typescript", "--output", filepath.Join(notDir, "out")}, exitWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(run(tt.args)); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}